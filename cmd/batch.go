@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// readBatches reads non-empty trimmed lines and sends them to fn in
+// batches of the given size. The last batch can be smaller. If there are
+// no lines, fn is called once with an empty batch.
+func readBatches(r io.Reader, batch int, fn func(lines []string, first bool)) {
+	sc := bufio.NewScanner(r)
+	lines := make([]string, 0, batch)
+	first := true
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) == batch {
+			fn(lines, first)
+			first = false
+			lines = lines[:0]
+		}
+	}
+	if err := sc.Err(); err != nil {
+		slog.Error("Cannot read input", "error", err)
+	}
+	if len(lines) > 0 || first {
+		fn(lines, first)
+	}
+}
+
+// parallelMap applies fn to all items using the given number of parallel
+// workers. The results keep the order of items.
+func parallelMap[T any](jobs int, items []string, fn func(string) T) []T {
+	res := make([]T, len(items))
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for range max(jobs, 1) {
+		wg.Go(func() {
+			for i := range idx {
+				res[i] = fn(items[i])
+			}
+		})
+	}
+	for i := range items {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+	return res
+}
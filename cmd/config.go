@@ -0,0 +1,547 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnsys"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/clean"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// configKey describes a setting of the configuration file.
+type configKey struct {
+	// name is the key in the configuration file and the name of cfgData
+	// field.
+	name string
+
+	// env is an environment variable that overrides the setting.
+	env string
+
+	// flag is a command line flag that overrides the setting.
+	flag string
+}
+
+// configKeys are all settings known to the configuration file, one per
+// field of cfgData.
+var configKeys = []configKey{
+	{"Batch", "GNV_BATCH", ""},
+	{"Cleaning", "GNV_CLEANING", "clean"},
+	{"DataSourceAliases", "GNV_DATA_SOURCE_ALIASES", ""},
+	{"DataSources", "GNV_DATA_SOURCES", "sources"},
+	{"DecisionsFile", "GNV_DECISIONS_FILE", "decisions"},
+	{"Dedup", "GNV_DEDUP", "dedup"},
+	{"Format", "GNV_FORMAT", "format"},
+	{"Jobs", "GNV_JOBS", "jobs"},
+	{"NamesNumThreshold", "GNV_NAMES_NUM_THRESHOLD", ""},
+	{"Port", "GNV_PORT", "port"},
+	{"Profile", "GNV_PROFILE", "profile"},
+	{"Profiles", "", ""},
+	{"Quiet", "GNV_QUIET", "quiet"},
+	{"Resolve", "GNV_RESOLVE", "resolve"},
+	{"VerificationTimeout", "GNV_VERIFICATION_TIMEOUT", "verification_timeout"},
+	{"VerifierURL", "GNV_VERIFIER_URL", "verifier_url"},
+	{"Vernaculars", "GNV_VERNACULARS", "vernaculars"},
+	{"WebAddress", "GNV_WEB_ADDRESS", "address"},
+	{"WebAPIKeysFile", "GNV_WEB_API_KEYS_FILE", "api_keys"},
+	{"WebBasePath", "GNV_WEB_BASE_PATH", "base_path"},
+	{"WebCORSHeaders", "GNV_WEB_CORS_HEADERS", "cors_headers"},
+	{"WebCORSMethods", "GNV_WEB_CORS_METHODS", "cors_methods"},
+	{"WebCORSOrigins", "GNV_WEB_CORS_ORIGINS", "cors_origins"},
+	{"WebIdleTimeout", "GNV_WEB_IDLE_TIMEOUT", "idle_timeout"},
+	{"WebPublicUI", "GNV_WEB_PUBLIC_UI", "public_ui"},
+	{"WebReadTimeout", "GNV_WEB_READ_TIMEOUT", "read_timeout"},
+	{"WebShutdownTimeout", "GNV_WEB_SHUTDOWN_TIMEOUT", "shutdown_timeout"},
+	{"WebTLSCert", "GNV_WEB_TLS_CERT", "tls_cert"},
+	{"WebTLSKey", "GNV_WEB_TLS_KEY", "tls_key"},
+	{"WebTLSSelfSigned", "GNV_WEB_TLS_SELF_SIGNED", "tls_self_signed"},
+	{"WebWriteTimeout", "GNV_WEB_WRITE_TIMEOUT", "write_timeout"},
+	{"WithAllMatches", "GNV_WITH_ALL_MATCHES", "all_matches"},
+	{"WithCapitalization", "GNV_WITH_CAPITALIZATION", "capitalize"},
+	{"WithRelaxedFuzzyMatch", "GNV_WITH_RELAXED_FUZZY_MATCH", "fuzzy_relaxed"},
+	{"WithSpeciesGroup", "GNV_WITH_SPECIES_GROUP", "species_group"},
+	{"WithUninomialFuzzyMatch", "GNV_WITH_UNINOMIAL_FUZZY_MATCH", "fuzzy_uninomial"},
+	{"Within", "GNV_WITHIN", "within"},
+}
+
+// configFilePath is the location of the configuration file. It is set by
+// initConfig.
+var configFilePath string
+
+// configCmd groups commands that work with settings.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Shows, validates and changes settings.",
+	Long: `Works with settings of gnverifier. Settings come from the
+configuration file, environment variables and command line flags, in the
+order of increasing priority.
+
+  examples:
+    gnverifier config show
+    gnverifier config show -s "1,11" -j 8
+    gnverifier config path
+    gnverifier config validate
+    gnverifier config set Jobs 8`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+// configShowCmd prints effective settings. It shares flags that change
+// settings with the root command (see configShowFlags).
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Shows effective settings and where they came from.",
+	Long: `Shows settings after merging the configuration file, environment
+variables and flags. The SOURCE column tells where a value came from:
+'default', 'file', an environment variable, or a flag. Flags of the main
+command can be given to see their effect.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		flags := []funcFlag{
+			capitalizeFlag, spGroupFlag, fuzzyRelaxedFlag,
+			fuzzyUninomialFlag, formatFlag, jobsFlag, allMatchesFlag,
+			verifierUrlFlag, sourcesFlag, vernacularsFlag, dedupFlag,
+			cleanFlag, resolveFlag, withinFlag, decisionsFlag,
+			verificationTimeoutFlag, webTimeoutsFlag, addressFlag,
+			basePathFlag, tlsFlag, apiKeysFlag, corsFlag,
+		}
+		for _, f := range flags {
+			f(cmd)
+		}
+		showConfig(cmd, config.New(opts...))
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Prints the path to the configuration file.",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		fmt.Println(configFilePath)
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Checks settings for unknown keys and bad values.",
+	Long: `Checks the configuration file and environment variables for unknown
+keys and bad values. If a file is given, only the file is checked.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		v := viper.GetViper()
+		path := configFilePath
+		if len(args) == 1 {
+			path = args[0]
+			v = viper.New()
+			v.SetConfigFile(path)
+			if err := v.ReadInConfig(); err != nil {
+				slog.Error("Cannot read config file", "file", path, "error", err)
+				os.Exit(1)
+			}
+		}
+		errs := validateConfig(v)
+		if len(errs) == 0 {
+			fmt.Printf("Settings are valid (%s).\n", path)
+			return
+		}
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		}
+		os.Exit(1)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set key value",
+	Short: "Changes a setting in the configuration file.",
+	Long: `Changes a setting in the configuration file. Lists are given as
+comma-separated values ("1,11"), aliases of data-sources as name=id pairs
+("worms=9,myflora=208"), durations in Go format ("30s", "2m").
+
+  examples:
+    gnverifier config set Format tsv
+    gnverifier config set DataSources 1,11
+    gnverifier config set WebReadTimeout 1m`,
+	Args: cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		err := setConfigValue(configFilePath, args[0], args[1])
+		if err != nil {
+			slog.Error("Cannot change setting", "error", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s is set to '%s' in %s.\n", args[0], args[1], configFilePath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd, configPathCmd, configValidateCmd,
+		configSetCmd)
+}
+
+// findConfigKey finds a known setting by its case-insensitive name.
+func findConfigKey(name string) (configKey, bool) {
+	for _, v := range configKeys {
+		if strings.EqualFold(v.name, name) {
+			return v, true
+		}
+	}
+	return configKey{}, false
+}
+
+// showConfig prints effective settings as a table.
+func showConfig(cmd *cobra.Command, cfg config.Config) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, k := range configKeys {
+		fmt.Fprintf(w, "%s\t%s\t%s\n",
+			k.name, configValue(cmd, cfg, k), configSource(cmd, k))
+	}
+	_ = w.Flush()
+}
+
+// configValue returns the effective value of a setting. Most of them
+// are in cfg, the rest are kept by the cmd package.
+func configValue(cmd *cobra.Command, cfg config.Config, k configKey) string {
+	switch k.name {
+	case "DataSourceAliases":
+		return configValueString(reflect.ValueOf(sourceAliases))
+	case "DecisionsFile":
+		return decisionsFile(cmd)
+	case "Port":
+		return strconv.Itoa(webPort(cmd))
+	case "Profile":
+		return profile
+	case "Profiles":
+		return strings.Join(knownProfiles, ",")
+	case "Quiet":
+		return strconv.FormatBool(isQuiet(cmd))
+	default:
+		return configValueString(reflect.ValueOf(cfg).FieldByName(k.name))
+	}
+}
+
+// configSource tells where the value of a setting came from.
+func configSource(cmd *cobra.Command, k configKey) string {
+	if k.flag != "" && cmd.Flags().Changed(k.flag) {
+		return "flag --" + k.flag
+	}
+	if k.env != "" {
+		if _, ok := os.LookupEnv(k.env); ok {
+			return "env " + k.env
+		}
+	}
+	if profile != "" && viper.InConfig("profiles."+profile+"."+k.name) {
+		return "profile " + profile
+	}
+	if viper.InConfig(k.name) {
+		return "file"
+	}
+	return "default"
+}
+
+func configValueString(v reflect.Value) string {
+	switch x := v.Interface().(type) {
+	case gnfmt.Format:
+		// the name that is used in the configuration file.
+		for _, v := range []string{"csv", "tsv", "compact", "pretty"} {
+			if f, _ := gnfmt.NewFormat(v); f == x {
+				return v
+			}
+		}
+		return ""
+	case time.Duration:
+		return x.String()
+	case []int:
+		ss := make([]string, len(x))
+		for i := range x {
+			ss[i] = strconv.Itoa(x[i])
+		}
+		return strings.Join(ss, ",")
+	case []string:
+		return strings.Join(x, ",")
+	case []clean.Step:
+		ss := make([]string, len(x))
+		for i := range x {
+			ss[i] = string(x[i])
+		}
+		return strings.Join(ss, ",")
+	case map[string]int:
+		var ss []string
+		for _, k := range slices.Sorted(maps.Keys(x)) {
+			ss = append(ss, fmt.Sprintf("%s=%d", k, x[k]))
+		}
+		return strings.Join(ss, ",")
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// validateConfig returns problems with settings: unknown keys, values of
+// wrong types and values out of range.
+func validateConfig(v *viper.Viper) []error {
+	var res []error
+	for _, k := range unknownConfigKeys(v) {
+		res = append(res, fmt.Errorf(
+			"unknown key '%s', see 'gnverifier config show' for known keys", k,
+		))
+	}
+	cfg := &cfgData{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return append(res, err)
+	}
+	res = append(res, cfg.check()...)
+
+	if cfg.Profile != "" {
+		if _, ok := cfg.Profiles[strings.ToLower(cfg.Profile)]; !ok {
+			res = append(res, fmt.Errorf("Profile '%s' is not in Profiles", cfg.Profile))
+		}
+	}
+	for _, name := range profileNames(cfg) {
+		var pcfg cfgData
+		if sub := v.Sub("profiles." + name); sub != nil {
+			if err := sub.Unmarshal(&pcfg); err != nil {
+				res = append(res, fmt.Errorf("profile '%s': %w", name, err))
+				continue
+			}
+		}
+		for _, err := range pcfg.check() {
+			res = append(res, fmt.Errorf("profile '%s': %w", name, err))
+		}
+	}
+	return res
+}
+
+// unknownConfigKeys returns keys of the configuration that are not
+// settings of gnverifier. Viper keeps keys in lower case.
+func unknownConfigKeys(v *viper.Viper) []string {
+	var res []string
+	for _, k := range v.AllKeys() {
+		// keys of DataSourceAliases and Profiles are nested.
+		key, rest, _ := strings.Cut(k, ".")
+		if key == "profiles" {
+			// profiles.<name>.<key>
+			name, pkey, _ := strings.Cut(rest, ".")
+			pkey, _, _ = strings.Cut(pkey, ".")
+			pk, ok := findConfigKey(pkey)
+			if ok && !strings.HasPrefix(pk.name, "Profile") {
+				continue
+			}
+			key = "profiles." + name + "." + pkey
+		}
+		if _, ok := findConfigKey(key); !ok && !slices.Contains(res, key) {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+// check finds values that have correct types but cannot be used.
+func (cfg cfgData) check() []error {
+	var res []error
+	if cfg.Format != "" {
+		if _, err := gnfmt.NewFormat(cfg.Format); err != nil {
+			res = append(res, fmt.Errorf(
+				"Format '%s' is unknown, use csv, tsv, compact or pretty", cfg.Format,
+			))
+		}
+	}
+	if _, err := clean.ParseSteps(cfg.Cleaning); err != nil {
+		res = append(res, fmt.Errorf("Cleaning: %w", err))
+	}
+	if cfg.DecisionsFile != "" {
+		if _, err := curation.Load(cfg.DecisionsFile); err != nil {
+			res = append(res, fmt.Errorf("DecisionsFile: %w", err))
+		}
+	}
+	if _, err := config.NewDedupMode(cfg.Dedup); err != nil {
+		res = append(res, fmt.Errorf("Dedup: %w", err))
+	}
+	if cfg.VerifierURL != "" {
+		u, err := url.Parse(cfg.VerifierURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+			res = append(res, fmt.Errorf(
+				"VerifierURL '%s' is not an HTTP(S) URL", cfg.VerifierURL,
+			))
+		}
+	}
+	for _, v := range cfg.Vernaculars {
+		if len(v) != 3 {
+			res = append(res, fmt.Errorf(
+				"Vernaculars '%s' is not a 3-letter language code", v,
+			))
+		}
+	}
+	for _, v := range cfg.DataSources {
+		if v < 0 {
+			res = append(res, fmt.Errorf("DataSources has negative ID %d", v))
+		}
+	}
+	for k, v := range cfg.DataSourceAliases {
+		if v < 1 {
+			res = append(res, fmt.Errorf(
+				"DataSourceAliases '%s' has wrong data-source ID %d", k, v,
+			))
+		}
+	}
+
+	cv := reflect.ValueOf(cfg)
+	for _, k := range configKeys {
+		f := cv.FieldByName(k.name)
+		switch f.Interface().(type) {
+		case int, time.Duration:
+			if f.Int() < 0 {
+				res = append(res, fmt.Errorf("%s cannot be negative", k.name))
+			}
+		}
+	}
+	return res
+}
+
+// parseConfigValue converts a command line value of a setting to the type
+// of the setting and checks it.
+func parseConfigValue(k configKey, s string) (any, error) {
+	var cfg cfgData
+	f := reflect.ValueOf(&cfg).Elem().FieldByName(k.name)
+	s = strings.TrimSpace(s)
+
+	var err error
+	switch f.Interface().(type) {
+	case string:
+		f.SetString(s)
+	case bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		f.SetBool(b)
+	case int:
+		var i int
+		i, err = strconv.Atoi(s)
+		f.SetInt(int64(i))
+	case time.Duration:
+		var d time.Duration
+		d, err = time.ParseDuration(s)
+		f.SetInt(int64(d))
+	case []string:
+		f.Set(reflect.ValueOf(parseList(s)))
+	case []int:
+		var ids []int
+		for _, v := range parseList(s) {
+			var id int
+			if id, err = strconv.Atoi(v); err != nil {
+				break
+			}
+			ids = append(ids, id)
+		}
+		f.Set(reflect.ValueOf(ids))
+	case map[string]int:
+		aliases := make(map[string]int)
+		for _, v := range parseList(s) {
+			name, id, ok := strings.Cut(v, "=")
+			if !ok {
+				err = errors.New("use name=id pairs")
+				break
+			}
+			if aliases[strings.TrimSpace(name)], err = strconv.Atoi(strings.TrimSpace(id)); err != nil {
+				break
+			}
+		}
+		f.Set(reflect.ValueOf(aliases))
+	default:
+		err = errors.New("edit the configuration file to change it")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bad value '%s' for %s: %w", s, k.name, err)
+	}
+	if errs := cfg.check(); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	if d, ok := f.Interface().(time.Duration); ok {
+		return d.String(), nil
+	}
+	return f.Interface(), nil
+}
+
+// setConfigValue changes a setting in the configuration file. If the
+// setting is already in the file, it is replaced, otherwise it is added
+// to the end of the file. Comments are preserved.
+func setConfigValue(path, key, value string) error {
+	k, ok := findConfigKey(key)
+	if !ok {
+		return fmt.Errorf(
+			"unknown key '%s', see 'gnverifier config show' for known keys", key,
+		)
+	}
+	val, err := parseConfigValue(k, value)
+	if err != nil {
+		return err
+	}
+	entry, err := yaml.Marshal(yaml.MapSlice{{Key: k.name, Value: val}})
+	if err != nil {
+		return err
+	}
+
+	if exists, _ := gnsys.FileExists(path); !exists {
+		createConfig(path)
+	}
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.SplitAfter(string(bs), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	start, end := -1, -1
+	for i, l := range lines {
+		if start < 0 {
+			name, _, ok := strings.Cut(l, ":")
+			if ok && strings.EqualFold(strings.TrimSpace(name), k.name) &&
+				!strings.HasPrefix(l, " ") {
+				start = i
+			}
+			continue
+		}
+		// the value of the setting can continue on indented lines, or on
+		// lines of a list.
+		if !strings.HasPrefix(l, " ") && !strings.HasPrefix(l, "- ") {
+			end = i
+			break
+		}
+	}
+
+	var res []string
+	switch {
+	case start < 0:
+		res = lines
+		if len(res) > 0 && !strings.HasSuffix(res[len(res)-1], "\n") {
+			res = append(res, "\n")
+		}
+		res = append(res, "\n", string(entry))
+	case end < 0:
+		res = append(lines[:start:start], string(entry))
+	default:
+		res = append(lines[:start:start], string(entry))
+		res = append(res, lines[end:]...)
+	}
+	return os.WriteFile(path, []byte(strings.Join(res, "")), 0o644)
+}
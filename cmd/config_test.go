@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigKeys(t *testing.T) {
+	typ := reflect.TypeFor[cfgData]()
+	require.Equal(t, typ.NumField(), len(configKeys))
+	for i := range typ.NumField() {
+		k, ok := findConfigKey(typ.Field(i).Name)
+		assert.True(t, ok, typ.Field(i).Name)
+		if k.flag != "" {
+			f := rootCmd.Flags().Lookup(k.flag)
+			if f == nil {
+				f = rootCmd.PersistentFlags().Lookup(k.flag)
+			}
+			assert.NotNil(t, f, k.flag)
+		}
+	}
+}
+
+func TestParseConfigValue(t *testing.T) {
+	tests := []struct {
+		key, val string
+		res      any
+		err      bool
+	}{
+		{"Format", "tsv", "tsv", false},
+		{"Format", "xml", nil, true},
+		{"Cleaning", "html, caps", []string{"html", "caps"}, false},
+		{"Cleaning", "spelling", nil, true},
+		{"Dedup", "normalized", "normalized", false},
+		{"Dedup", "fuzzy", nil, true},
+		{"Jobs", "8", 8, false},
+		{"Jobs", "many", nil, true},
+		{"Jobs", "-1", nil, true},
+		{"Resolve", "true", true, false},
+		{"Within", "Aves", "Aves", false},
+		{"WithAllMatches", "true", true, false},
+		{"WithAllMatches", "maybe", nil, true},
+		{"WebReadTimeout", "90s", "1m30s", false},
+		{"WebReadTimeout", "soon", nil, true},
+		{"DataSources", "1, 11", []int{1, 11}, false},
+		{"DataSources", "1,col", nil, true},
+		{"Vernaculars", "eng,deu", []string{"eng", "deu"}, false},
+		{"Vernaculars", "english", nil, true},
+		{"DataSourceAliases", "worms=9, flora = 208",
+			map[string]int{"worms": 9, "flora": 208}, false},
+		{"DataSourceAliases", "worms", nil, true},
+		{"VerifierURL", "http://localhost:8888/api/v1/",
+			"http://localhost:8888/api/v1/", false},
+		{"VerifierURL", "localhost", nil, true},
+	}
+	for _, v := range tests {
+		k, ok := findConfigKey(v.key)
+		require.True(t, ok)
+		res, err := parseConfigValue(k, v.val)
+		if v.err {
+			assert.Error(t, err, v.key+" "+v.val)
+			continue
+		}
+		require.NoError(t, err, v.key+" "+v.val)
+		assert.Equal(t, v.res, res, v.key+" "+v.val)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gnverifier.yaml")
+	txt := `Format: xml
+Jobs: many
+Fromat: csv
+DataSourceAliases:
+  worms: 9
+`
+	require.NoError(t, os.WriteFile(path, []byte(txt), 0o644))
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	errs := validateConfig(v)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "fromat")
+	assert.Contains(t, errs[1].Error(), "Jobs")
+
+	txt = "Format: xml\nWebReadTimeout: -1s\n"
+	require.NoError(t, os.WriteFile(path, []byte(txt), 0o644))
+	require.NoError(t, v.ReadInConfig())
+	assert.Len(t, validateConfig(v), 2)
+
+	txt = configText + "Format: tsv\nDataSourceAliases:\n  worms: 9\n"
+	require.NoError(t, os.WriteFile(path, []byte(txt), 0o644))
+	require.NoError(t, v.ReadInConfig())
+	assert.Empty(t, validateConfig(v))
+}
+
+func TestSetConfigValue(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gnverifier.yaml")
+	txt := `# Jobs is number of jobs.
+#
+# Jobs: 4
+DataSources:
+  - 1
+  - 3
+
+# Format of the output.
+Format: csv
+`
+	require.NoError(t, os.WriteFile(path, []byte(txt), 0o644))
+
+	require.NoError(t, setConfigValue(path, "datasources", "11,12,13"))
+	require.NoError(t, setConfigValue(path, "Format", "tsv"))
+	require.NoError(t, setConfigValue(path, "Jobs", "8"))
+	assert.Error(t, setConfigValue(path, "Jobz", "8"))
+	assert.Error(t, setConfigValue(path, "Jobs", "-8"))
+
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(bs), "# Jobs is number of jobs.")
+	assert.Contains(t, string(bs), "# Format of the output.")
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	assert.Equal(t, []int{11, 12, 13}, v.GetIntSlice("DataSources"))
+	assert.Equal(t, "tsv", v.GetString("Format"))
+	assert.Equal(t, 8, v.GetInt("Jobs"))
+	assert.Empty(t, validateConfig(v))
+
+	// a new file is created from the template.
+	path = filepath.Join(dir, "new", "gnverifier.yaml")
+	require.NoError(t, setConfigValue(path, "WebReadTimeout", "1m"))
+	v = viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	assert.Equal(t, "1m0s", v.GetString("WebReadTimeout"))
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnsys"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/conflict"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/io/verifrest"
+	"github.com/spf13/cobra"
+)
+
+// conflictsCmd finds names on which data-sources disagree.
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts [name|file]",
+	Short: "Finds names on which data-sources disagree.",
+	Long: `Verifies names against several data-sources and reports names that
+need attention of a curator:
+
+  status        accepted in one source and a synonym in another,
+  acceptedName  sources have different accepted names,
+  kingdom       sources place the name into different kingdoms,
+  homonym       one source has several taxa with the name
+                (needs --all_matches).
+
+Only names with conflicts are printed to standard output, one row per
+conflict. A summary for humans is printed to standard error.
+
+  examples:
+    gnverifier conflicts names.txt -s "col,itis,gbif" > conflicts.csv
+    gnverifier conflicts "Morus alba" -s "1,3,11" -M -f pretty`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := []funcFlag{
+			formatFlag, jobsFlag, allMatchesFlag, verifierUrlFlag,
+			sourcesFlag, quietFlag,
+		}
+		for _, f := range flags {
+			f(cmd)
+		}
+
+		cfg := config.New(opts...)
+		if len(cfg.DataSources) < 2 && !isAllSources(cfg.DataSources) {
+			slog.Error("Set at least two data-sources with --sources")
+			os.Exit(1)
+		}
+		vfr := verifrest.New(cfg.VerifierURL)
+		gnv := gnverifier.New(cfg, vfr)
+
+		var r io.Reader
+		switch {
+		case len(args) == 0:
+			if !checkStdin() {
+				_ = cmd.Help()
+				return
+			}
+			r = os.Stdin
+		default:
+			if exists, _ := gnsys.FileExists(args[0]); !exists {
+				r = strings.NewReader(args[0])
+				break
+			}
+			f, err := os.Open(args[0])
+			if err != nil {
+				slog.Error("Cannot open file", "error", err, "file", args[0])
+				os.Exit(1)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		ctx, stop := signal.NotifyContext(
+			context.Background(), syscall.SIGINT, syscall.SIGTERM,
+		)
+		defer stop()
+		sum, err := findConflicts(ctx, gnv, r, os.Stdout)
+		if quiet, _ := cmd.Flags().GetBool("quiet"); !quiet {
+			fmt.Fprint(os.Stderr, sum.String())
+		}
+		if err != nil {
+			slog.Error("Verification failed for some names", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(conflictsCmd)
+	fs := conflictsCmd.Flags()
+	fs.StringP("format", "f", "", `Format of the output: "compact", "pretty", "csv", "tsv".`)
+	fs.StringP("sources", "s", "",
+		`IDs or names of data-sources to compare (ex "1,11", "col,itis").`)
+	fs.BoolP("all_matches", "M", false, "use all matched results per source to find homonyms.")
+	fs.IntP("jobs", "j", 4, "Number of jobs running in parallel.")
+	fs.StringP("verifier_url", "v", "", "URL for verification service.")
+	fs.BoolP("quiet", "q", false, "do not show progress and summary")
+}
+
+func isAllSources(ds []int) bool {
+	return len(ds) == 1 && ds[0] == 0
+}
+
+// findConflicts verifies names from a reader, one name per line, and
+// writes conflicts of names to a writer in the order of the input. It
+// returns an error if the input cannot be read to the end.
+func findConflicts(
+	ctx context.Context,
+	gnv gnverifier.GNverifier,
+	r io.Reader,
+	w io.Writer,
+) (conflict.Summary, error) {
+	var sum conflict.Summary
+	f := gnv.Config().Format
+	// readErr is set before the iteration of names ends.
+	var readErr error
+	names := func(yield func(string) bool) {
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			if !yield(strings.TrimSpace(sc.Text())) {
+				return
+			}
+		}
+		readErr = sc.Err()
+	}
+
+	if f == gnfmt.CSV || f == gnfmt.TSV {
+		fmt.Fprintln(w, output.ConflictsHeader(f))
+	}
+	var res error
+	for ver, err := range gnv.VerifyIter(ctx, names) {
+		if err != nil {
+			if res == nil {
+				res = err
+			}
+			continue
+		}
+		rec := conflict.Find(ver)
+		sum.Add(rec)
+		if out := output.ConflictsOutput(rec, f); out != "" {
+			fmt.Fprintln(w, out)
+		}
+	}
+	if readErr != nil {
+		return sum, fmt.Errorf("cannot read names: %w", readErr)
+	}
+	return sum, res
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	vtest "github.com/gnames/gnverifier/pkg/ent/verifier/verifiertesting"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindConflicts(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(
+		_ context.Context,
+		inp vlib.Input,
+	) (vlib.Output, error) {
+		var res vlib.Output
+		for _, v := range inp.NameStrings {
+			name := vlib.Name{Name: v}
+			if v == "Felis leo" {
+				name.Results = []*vlib.ResultData{
+					{DataSourceID: 1, TaxonomicStatus: vlib.SynonymTaxStatus,
+						CurrentCanonicalSimple: "Panthera leo"},
+					{DataSourceID: 11, TaxonomicStatus: vlib.AcceptedTaxStatus,
+						CurrentCanonicalSimple: "Felis leo"},
+				}
+			}
+			res.Names = append(res.Names, name)
+		}
+		return res, nil
+	})
+	cfg := config.New(config.OptDataSources([]int{1, 11}))
+	gnv := gnverifier.New(cfg, vfr)
+
+	var w bytes.Buffer
+	r := strings.NewReader("Bubo bubo\nFelis leo\n")
+	sum, err := findConflicts(context.Background(), gnv, r, &w)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, sum.Names)
+	assert.Equal(t, 1, sum.ConflictNames)
+	assert.Equal(t,
+		"ScientificName,Conflict,Values\n"+
+			"Felis leo,status,1: Synonym; 11: Accepted\n"+
+			"Felis leo,acceptedName,1: Panthera leo; 11: Felis leo\n",
+		w.String())
+}
+
+func TestFindConflictsReadError(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	gnv := gnverifier.New(config.New(), vfr)
+
+	inputs := []io.Reader{
+		io.MultiReader(
+			strings.NewReader("Bubo bubo\n"),
+			iotest.ErrReader(errors.New("disk failure")),
+		),
+		strings.NewReader("Bubo bubo\n" + strings.Repeat("a", bufio.MaxScanTokenSize+1)),
+	}
+	for _, r := range inputs {
+		var w bytes.Buffer
+		_, err := findConflicts(context.Background(), gnv, r, &w)
+		assert.ErrorContains(t, err, "cannot read names")
+	}
+}
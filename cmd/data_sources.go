@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/io/verifrest"
+)
+
+// sourcesCacheAge is the age after which the cached list of data-sources
+// is refreshed from the verification service.
+const sourcesCacheAge = 7 * 24 * time.Hour
+
+// sourceAliases are user-defined names of data-sources from the
+// DataSourceAliases setting of the configuration file.
+var sourceAliases map[string]int
+
+// sourceResolver finds data-source IDs by their names. The list of
+// data-sources is kept in a cache file, so names can be resolved offline.
+type sourceResolver struct {
+	aliases   map[string]int
+	cachePath string
+	fetch     func() ([]vlib.DataSource, error)
+
+	dss    []vlib.DataSource
+	cached bool
+}
+
+func newSourceResolver(verifierURL string) *sourceResolver {
+	vfr := verifrest.New(verifierURL)
+	return &sourceResolver{
+		aliases:   sourceAliases,
+		cachePath: sourcesCachePath(),
+		fetch: func() ([]vlib.DataSource, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+			return vfr.DataSources(ctx)
+		},
+	}
+}
+
+// sourcesCachePath returns the location of the cached list of
+// data-sources, or an empty string if there is no cache directory.
+func sourcesCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gnverifier", "data_sources.json")
+}
+
+// parseDataSources converts a comma-separated list of data-source IDs,
+// short titles or aliases into IDs. Names are resolved only if there is
+// at least one of them in the list.
+func parseDataSources(s string, r *sourceResolver) ([]int, error) {
+	var res []int
+	for _, v := range parseList(s) {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			if r == nil {
+				return nil, fmt.Errorf("data-source '%s' is not an ID", v)
+			}
+			if id, err = r.resolve(v); err != nil {
+				return nil, err
+			}
+		}
+		if id < 0 {
+			slog.Warn("Data source ID is less than zero, skipping", "input", id)
+			continue
+		}
+		if !slices.Contains(res, id) {
+			res = append(res, id)
+		}
+	}
+	return res, nil
+}
+
+// resolve finds an ID of a data-source by an alias, short title, title,
+// the first word of short title, or an acronym of short title. Names are
+// case-insensitive.
+func (r *sourceResolver) resolve(name string) (int, error) {
+	key := normSourceName(name)
+	for k, v := range r.aliases {
+		if normSourceName(k) == key {
+			return v, nil
+		}
+	}
+
+	if err := r.load(false); err != nil {
+		return 0, err
+	}
+	ds, err := matchSource(r.dss, key)
+	if errors.Is(err, errUnknownSource) && r.cached {
+		// the source might be new, try a fresh list.
+		if r.load(true) == nil {
+			ds, err = matchSource(r.dss, key)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("data-source '%s': %w", name, err)
+	}
+	return ds.ID, nil
+}
+
+var errUnknownSource = errors.New(
+	"unknown name, see 'gnverifier sources' for the list of data-sources",
+)
+
+// matchSource finds a data-source by a normalized name. Rules are applied
+// from the most to the least strict, and the first rule with matches
+// wins.
+func matchSource(dss []vlib.DataSource, key string) (vlib.DataSource, error) {
+	rules := []func(vlib.DataSource) string{
+		func(ds vlib.DataSource) string { return normSourceName(ds.TitleShort) },
+		func(ds vlib.DataSource) string { return normSourceName(ds.Title) },
+		func(ds vlib.DataSource) string {
+			words := strings.Fields(ds.TitleShort)
+			if len(words) == 0 {
+				return ""
+			}
+			return normSourceName(words[0])
+		},
+		func(ds vlib.DataSource) string { return acronym(ds.TitleShort) },
+	}
+	for _, rule := range rules {
+		var found []vlib.DataSource
+		for _, ds := range dss {
+			if v := rule(ds); v != "" && v == key {
+				found = append(found, ds)
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			var ids []string
+			for _, ds := range found {
+				ids = append(ids, fmt.Sprintf("%d (%s)", ds.ID, ds.TitleShort))
+			}
+			return vlib.DataSource{}, fmt.Errorf(
+				"ambiguous name, matches %s", strings.Join(ids, ", "),
+			)
+		}
+	}
+	return vlib.DataSource{}, errUnknownSource
+}
+
+// load reads the list of data-sources from the cache, or from the
+// verification service if the cache is missing, stale, or refresh is
+// true. Stale cache is used when the service is not available.
+func (r *sourceResolver) load(refresh bool) error {
+	if r.dss != nil && !refresh {
+		return nil
+	}
+
+	var stale []vlib.DataSource
+	if !refresh {
+		dss, fresh := readSourcesCache(r.cachePath)
+		if fresh {
+			r.dss, r.cached = dss, true
+			return nil
+		}
+		stale = dss
+	}
+
+	dss, err := r.fetch()
+	if err == nil && len(dss) > 0 {
+		r.dss, r.cached = dss, false
+		writeSourcesCache(r.cachePath, dss)
+		return nil
+	}
+	if len(stale) > 0 {
+		slog.Warn("Cannot get data-sources, using cached list", "error", err)
+		r.dss, r.cached = stale, false
+		return nil
+	}
+	if r.dss != nil {
+		return err
+	}
+	return fmt.Errorf("cannot get list of data-sources: %w", err)
+}
+
+// readSourcesCache returns cached data-sources and true if the cache is
+// younger than sourcesCacheAge.
+func readSourcesCache(path string) ([]vlib.DataSource, bool) {
+	if path == "" {
+		return nil, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var res []vlib.DataSource
+	if err = json.Unmarshal(bs, &res); err != nil {
+		slog.Warn("Cannot parse data-sources cache", "file", path, "error", err)
+		return nil, false
+	}
+	return res, time.Since(info.ModTime()) < sourcesCacheAge
+}
+
+// writeSourcesCache saves data-sources for offline name resolution.
+func writeSourcesCache(path string, dss []vlib.DataSource) {
+	if path == "" {
+		return
+	}
+	bs, err := json.Marshal(dss)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, bs, 0o644)
+	}
+	if err != nil {
+		slog.Warn("Cannot cache data-sources", "file", path, "error", err)
+	}
+}
+
+func normSourceName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// acronym creates a lowercase acronym from first letters of words,
+// for example "col" from "Catalogue of Life".
+func acronym(s string) string {
+	var res []rune
+	for _, w := range strings.Fields(s) {
+		r := []rune(w)[0]
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			res = append(res, unicode.ToLower(r))
+		}
+	}
+	return string(res)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/diff"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd compares two verification runs.
+var diffCmd = &cobra.Command{
+	Use:   "diff old-output new-output",
+	Short: "Compares results of two verification runs.",
+	Long: `Compares two files created by gnverifier (CSV, TSV, JSON lines or
+JSON arrays) and reports names whose best match, current name, taxonomic
+status, data-source or match type changed. The report is printed to
+standard output, a summary for humans is printed to standard error.
+
+  examples:
+    gnverifier diff march.csv april.csv > changes.csv
+    gnverifier diff march.json april.json -f pretty`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		formatFlag(cmd)
+		frmt := config.New(opts...).Format
+
+		runs := make([][]diff.Record, 2)
+		for i, path := range args {
+			f, err := os.Open(path)
+			if err != nil {
+				slog.Error("Cannot open file", "file", path, "error", err)
+				os.Exit(1)
+			}
+			runs[i], err = diff.Load(f)
+			f.Close()
+			if err != nil {
+				slog.Error("Cannot read verification results", "file", path, "error", err)
+				os.Exit(1)
+			}
+		}
+
+		rep := diff.Compare(runs[0], runs[1])
+		fmt.Println(output.DiffOutput(rep, frmt))
+		if quiet, _ := cmd.Flags().GetBool("quiet"); !quiet {
+			fmt.Fprint(os.Stderr, rep.Summary())
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringP("format", "f", "",
+		`Format of the report: "compact", "pretty", "csv", "tsv".`)
+	diffCmd.Flags().BoolP("quiet", "q", false, "do not show summary")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// envCmd lists environment variables that change settings.
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Lists GNV_* environment variables and their values.",
+	Long: `Lists environment variables that change settings of gnverifier,
+together with their values in the current environment and effective
+values of the settings. Environment variables override the configuration
+file, flags override environment variables.
+
+With --dotenv, effective settings are printed as an environment file,
+for example for 'docker run --env-file'.
+
+  examples:
+    gnverifier env
+    gnverifier env --dotenv > gnverifier.env`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		cfg := config.New(opts...)
+		if dotenv, _ := cmd.Flags().GetBool("dotenv"); dotenv {
+			for _, k := range configKeys {
+				val := configValue(cmd, cfg, k)
+				if k.env != "" && val != "" {
+					fmt.Printf("%s=%s\n", k.env, val)
+				}
+			}
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VARIABLE\tSETTING\tENV VALUE\tEFFECTIVE VALUE")
+		for _, k := range configKeys {
+			if k.env == "" {
+				continue
+			}
+			val, ok := os.LookupEnv(k.env)
+			if !ok {
+				val = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				k.env, k.name, val, configValue(cmd, cfg, k))
+		}
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().Bool("dotenv", false,
+		"print effective settings as KEY=value lines.")
+}
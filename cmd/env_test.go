@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVars(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, k := range configKeys {
+		if k.env == "" {
+			assert.Equal(t, "Profiles", k.name)
+			continue
+		}
+		assert.True(t, strings.HasPrefix(k.env, "GNV_"), k.env)
+		assert.False(t, seen[k.env], k.env)
+		seen[k.env] = true
+	}
+}
+
+func TestWebPortQuiet(t *testing.T) {
+	defer func(p int, q bool) { cfgPort, cfgQuiet = p, q }(cfgPort, cfgQuiet)
+	cfgPort, cfgQuiet = 8181, true
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().IntP("port", "p", 0, "")
+		cmd.Flags().BoolP("quiet", "q", false, "")
+		return cmd
+	}
+
+	cmd := newCmd()
+	require.NoError(t, cmd.ParseFlags(nil))
+	assert.Equal(t, 8181, webPort(cmd))
+	assert.True(t, isQuiet(cmd))
+
+	cmd = newCmd()
+	require.NoError(t, cmd.ParseFlags([]string{"-p", "8888", "-q=false"}))
+	assert.Equal(t, 8888, webPort(cmd))
+	assert.False(t, isQuiet(cmd))
+}
+
+func TestConfigValue(t *testing.T) {
+	defer func(p int) { cfgPort = p }(cfgPort)
+	cfgPort = 8181
+	cmd := &cobra.Command{Use: "test"}
+	cfg := config.New(
+		config.OptFormat(gnfmt.PrettyJSON),
+		config.OptDataSources([]int{1, 11}),
+	)
+
+	tests := []struct{ key, val string }{
+		{"Format", "pretty"},
+		{"DataSources", "1,11"},
+		{"Port", "8181"},
+		{"Quiet", "false"},
+		{"WebReadTimeout", "5m0s"},
+	}
+	for _, v := range tests {
+		k, ok := findConfigKey(v.key)
+		require.True(t, ok)
+		assert.Equal(t, v.val, configValue(cmd, cfg, k), v.key)
+	}
+}
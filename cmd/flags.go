@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gnames/gnfmt"
 	gnverifier "github.com/gnames/gnverifier/pkg"
 	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/clean"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
 	"github.com/spf13/cobra"
 )
 
@@ -24,12 +26,54 @@ func versionFlag(cmd *cobra.Command) {
 }
 
 func quietFlag(cmd *cobra.Command) {
-	quiet, _ := cmd.Flags().GetBool("quiet")
-	if quiet {
+	if isQuiet(cmd) {
 		slog.SetLogLoggerLevel(10)
 	}
 }
 
+// isQuiet returns the quiet flag, or the Quiet setting if the flag is not
+// given.
+func isQuiet(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("quiet") {
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		return quiet
+	}
+	return cfgQuiet
+}
+
+// decisionsFile returns the decisions flag, or the DecisionsFile setting
+// if the flag is not given.
+func decisionsFile(cmd *cobra.Command) string {
+	if cmd.Flags().Changed("decisions") {
+		path, _ := cmd.Flags().GetString("decisions")
+		return path
+	}
+	return cfgDecisionsFile
+}
+
+func decisionsFlag(cmd *cobra.Command) {
+	path := decisionsFile(cmd)
+	if path == "" {
+		return
+	}
+	ds, err := curation.Load(path)
+	if err != nil {
+		slog.Error("Cannot use --decisions", "error", err)
+		os.Exit(1)
+	}
+	opts = append(opts, config.OptDecisions(ds))
+}
+
+// webPort returns the port flag, or the Port setting if the flag is not
+// given. Zero means that web GUI does not run.
+func webPort(cmd *cobra.Command) int {
+	if cmd.Flags().Changed("port") {
+		port, _ := cmd.Flags().GetInt("port")
+		return port
+	}
+	return cfgPort
+}
+
 func capitalizeFlag(cmd *cobra.Command) {
 	caps, _ := cmd.Flags().GetBool("capitalize")
 	if caps {
@@ -58,6 +102,13 @@ func fuzzyUninomialFlag(cmd *cobra.Command) {
 	}
 }
 
+func withinFlag(cmd *cobra.Command) {
+	s, _ := cmd.Flags().GetString("within")
+	if s != "" {
+		opts = append(opts, config.OptWithin(s))
+	}
+}
+
 func formatFlag(cmd *cobra.Command) {
 	formatString, _ := cmd.Flags().GetString("format")
 	if formatString == "" {
@@ -73,6 +124,32 @@ func formatFlag(cmd *cobra.Command) {
 	opts = append(opts, config.OptFormat(frmt))
 }
 
+func dedupFlag(cmd *cobra.Command) {
+	s, _ := cmd.Flags().GetString("dedup")
+	if s == "" {
+		return
+	}
+	dedup, err := config.NewDedupMode(s)
+	if err != nil {
+		slog.Error("Cannot use --dedup", "error", err)
+		os.Exit(1)
+	}
+	opts = append(opts, config.OptDedup(dedup))
+}
+
+func cleanFlag(cmd *cobra.Command) {
+	s, _ := cmd.Flags().GetString("clean")
+	if s == "" {
+		return
+	}
+	steps, err := clean.ParseSteps(strings.Split(s, ","))
+	if err != nil {
+		slog.Error("Cannot use --clean", "error", err)
+		os.Exit(1)
+	}
+	opts = append(opts, config.OptCleaning(steps))
+}
+
 func jobsFlag(cmd *cobra.Command) {
 	jobs, _ := cmd.Flags().GetInt("jobs")
 	if jobs != 4 && jobs > 0 {
@@ -87,12 +164,25 @@ func allMatchesFlag(cmd *cobra.Command) {
 	}
 }
 
+func resolveFlag(cmd *cobra.Command) {
+	resolve, _ := cmd.Flags().GetBool("resolve")
+	if resolve {
+		opts = append(opts, config.OptResolve(true))
+	}
+}
+
 func sourcesFlag(cmd *cobra.Command) {
 	sources, _ := cmd.Flags().GetString("sources")
-	if sources != "" {
-		data_sources := parseDataSources(sources)
-		opts = append(opts, config.OptDataSources(data_sources))
+	if sources == "" {
+		return
+	}
+	r := newSourceResolver(config.New(opts...).VerifierURL)
+	dss, err := parseDataSources(sources, r)
+	if err != nil {
+		slog.Error("Cannot use --sources", "error", err)
+		os.Exit(1)
 	}
+	opts = append(opts, config.OptDataSources(dss))
 }
 
 func verifierUrlFlag(cmd *cobra.Command) {
@@ -103,6 +193,90 @@ func verifierUrlFlag(cmd *cobra.Command) {
 	}
 }
 
+// verificationTimeoutFlag sets the timeout if the flag is given, so zero
+// turns the timeout off.
+func verificationTimeoutFlag(cmd *cobra.Command) {
+	if cmd.Flags().Changed("verification_timeout") {
+		d, _ := cmd.Flags().GetDuration("verification_timeout")
+		opts = append(opts, config.OptVerificationTimeout(d))
+	}
+}
+
+func webTimeoutsFlag(cmd *cobra.Command) {
+	timeouts := []struct {
+		flag string
+		opt  func(time.Duration) config.Option
+	}{
+		{"read_timeout", config.OptWebReadTimeout},
+		{"write_timeout", config.OptWebWriteTimeout},
+		{"idle_timeout", config.OptWebIdleTimeout},
+		{"shutdown_timeout", config.OptWebShutdownTimeout},
+	}
+	for _, v := range timeouts {
+		d, _ := cmd.Flags().GetDuration(v.flag)
+		if d > 0 {
+			opts = append(opts, v.opt(d))
+		}
+	}
+}
+
+func addressFlag(cmd *cobra.Command) {
+	addr, _ := cmd.Flags().GetString("address")
+	if addr != "" {
+		opts = append(opts, config.OptWebAddress(addr))
+	}
+}
+
+func basePathFlag(cmd *cobra.Command) {
+	base, _ := cmd.Flags().GetString("base_path")
+	if base != "" {
+		opts = append(opts, config.OptWebBasePath(base))
+	}
+}
+
+func tlsFlag(cmd *cobra.Command) {
+	cert, _ := cmd.Flags().GetString("tls_cert")
+	key, _ := cmd.Flags().GetString("tls_key")
+	selfSigned, _ := cmd.Flags().GetBool("tls_self_signed")
+	if cert != "" {
+		opts = append(opts, config.OptWebTLSCert(cert))
+	}
+	if key != "" {
+		opts = append(opts, config.OptWebTLSKey(key))
+	}
+	if selfSigned {
+		opts = append(opts, config.OptWebTLSSelfSigned(true))
+	}
+}
+
+func apiKeysFlag(cmd *cobra.Command) {
+	path, _ := cmd.Flags().GetString("api_keys")
+	publicUI, _ := cmd.Flags().GetBool("public_ui")
+	if path != "" {
+		opts = append(opts, config.OptWebAPIKeysFile(path))
+	}
+	if publicUI {
+		opts = append(opts, config.OptWebPublicUI(true))
+	}
+}
+
+func corsFlag(cmd *cobra.Command) {
+	cors := []struct {
+		flag string
+		opt  func([]string) config.Option
+	}{
+		{"cors_origins", config.OptWebCORSOrigins},
+		{"cors_methods", config.OptWebCORSMethods},
+		{"cors_headers", config.OptWebCORSHeaders},
+	}
+	for _, v := range cors {
+		s, _ := cmd.Flags().GetString(v.flag)
+		if ss := parseList(s); len(ss) > 0 {
+			opts = append(opts, v.opt(ss))
+		}
+	}
+}
+
 func vernacularsFlag(cmd *cobra.Command) {
 	vernLangs, _ := cmd.Flags().GetString("vernaculars")
 	if vernLangs != "" {
@@ -111,29 +285,16 @@ func vernacularsFlag(cmd *cobra.Command) {
 	}
 }
 
-func parseDataSources(s string) []int {
-	if s == "" {
-		return nil
-	}
-	dss := strings.Split(s, ",")
-	res := make([]int, 0, len(dss))
-	for _, v := range dss {
-		v = strings.Trim(v, " ")
-		ds, err := strconv.Atoi(v)
-		if err != nil {
-			slog.Warn("Cannot convert data-sources to list, skipping", "input", v)
-			return nil
-		}
-		if ds < 0 {
-			slog.Warn("Data source ID is less than zero, skipping", "input", ds)
-		} else {
-			res = append(res, int(ds))
+// parseList splits a comma-separated string into trimmed non-empty
+// elements.
+func parseList(s string) []string {
+	var res []string
+	for v := range strings.SplitSeq(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
 		}
 	}
-	if len(res) > 0 {
-		return res
-	}
-	return nil
+	return res
 }
 
 func parseVernacularLanguages(langs string) []string {
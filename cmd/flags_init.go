@@ -11,10 +11,38 @@ func initFlags() {
 
 func baseFlags() {
 	rootCmd.Flags().BoolP("version", "V", false, "Prints version information")
+	rootCmd.PersistentFlags().String("profile", "",
+		"name of a profile from the configuration file to use.")
 }
 
 func webFlags() {
 	rootCmd.Flags().IntP("port", "p", 0, "Port to run web GUI.")
+	rootCmd.Flags().String("address", "",
+		"IP address or host name for web GUI to bind to (default all interfaces).")
+	rootCmd.Flags().String("base_path", "",
+		`URL prefix to mount web GUI under (e.g. "/tools/gnverifier/").`)
+	rootCmd.Flags().String("tls_cert", "", "path to TLS certificate to serve web GUI via HTTPS.")
+	rootCmd.Flags().String("tls_key", "", "path to TLS private key for tls_cert.")
+	rootCmd.Flags().Bool("tls_self_signed", false,
+		"serve web GUI via HTTPS with self-signed certificate (development only).")
+	rootCmd.Flags().String("api_keys", "",
+		"path to YAML file with API keys and quotas; enables authentication.")
+	rootCmd.Flags().Bool("public_ui", false,
+		"allow access to HTML pages without API key when api_keys is set.")
+	rootCmd.Flags().String("cors_origins", "",
+		`origins allowed to call web GUI from browsers (e.g. "https://a.org,https://b.org" or "*").`)
+	rootCmd.Flags().String("cors_methods", "",
+		`HTTP methods allowed for CORS requests (default "GET,HEAD,POST").`)
+	rootCmd.Flags().String("cors_headers", "",
+		"request headers allowed for CORS requests (default any requested).")
+	rootCmd.Flags().Duration("read_timeout", 0,
+		"web server timeout for reading a request (e.g. \"30s\", default 5m).")
+	rootCmd.Flags().Duration("write_timeout", 0,
+		"web server timeout for writing a response (default 5m).")
+	rootCmd.Flags().Duration("idle_timeout", 0,
+		"web server timeout for idle keep-alive connections (default 2m).")
+	rootCmd.Flags().Duration("shutdown_timeout", 0,
+		"time given to in-flight web requests on SIGINT/SIGTERM (default 30s).")
 }
 
 func verificationFlags() {
@@ -26,8 +54,14 @@ func verificationFlags() {
 	rootCmd.Flags().BoolP("species_group", "G", false, "searching for species names also searches their species groups.")
 	rootCmd.Flags().BoolP("fuzzy_relaxed", "R", false,
 		"relaxes fuzzy matching rules, decreses max names to 50.")
+	rootCmd.Flags().String("within", "",
+		`higher taxon where names are expected to belong (e.g., "Aves").
+  Results from the taxon are preferred, names with the best match
+  outside of it are reported.`)
 	rootCmd.Flags().BoolP("fuzzy_uninomial", "U", false,
 		"allows fuzzy matching for uninomial names.")
+	rootCmd.Flags().Duration("verification_timeout", 0,
+		"maximum time to verify one batch of names in web GUI, 0 means no timeout (default 20s).")
 	rootCmd.Flags().StringP(
 		"vernaculars", "r", "",
 		`sets languages for vernacular names search (e.g., "eng,deu,rus")
@@ -42,18 +76,33 @@ func formatFlags() {
   compact: compact JSON,
   pretty: pretty JSON,
   csv: CSV (DEFAULT)`)
+	rootCmd.Flags().String("clean", "",
+		`cleans input names before verification, "all" or a comma-separated
+  list of "mojibake", "html", "whitespace", "punctuation", "qualifiers",
+  "caps". Original strings are added to the output.`)
+	rootCmd.Flags().String("decisions", "",
+		`CSV, TSV or YAML file with decisions of curators. A decision maps a
+  name to a record ID of a data-source, or rejects it. Changed names
+  are marked in the output.`)
+	rootCmd.Flags().Bool("resolve", false,
+		`returns accepted names of input, one per data-source, and flags
+  names with different accepted names in different sources.`)
 }
 
 func performanceFlags() {
 	rootCmd.Flags().IntP("jobs", "j", 4, "Number of jobs running in parallel.")
+	rootCmd.Flags().String("dedup", "",
+		`verify repeated names once: "exact", or "normalized" (ignores
+  whitespace and case), default "none".`)
 }
 
 func dataSourcesFlags() {
-	rootCmd.Flags().StringP("sources", "s", "", `IDs of important data-sources to verify against (ex "1,11").
+	rootCmd.Flags().StringP("sources", "s", "", `IDs or names of important data-sources to verify against (ex "1,11", "col,gbif").
   If sources are set and there are matches to their data,
   such matches are returned in "preferred_result" results.
   If the option is set to "0" all matched sources are returned.
 
+  Names are short titles or their acronyms, see "gnverifier sources".
   To find IDs refer to "https://verifier.globalnames.org/data_sources".
   1 - Catalogue of Life
   3 - ITIS
@@ -69,3 +118,14 @@ func dataSourcesFlags() {
   194 - PLAZI
   195 - AlgaeBase`)
 }
+
+// configShowFlags shares flags that change settings with 'config show',
+// so it can show their effect.
+func configShowFlags() {
+	for _, k := range configKeys {
+		// persistent flags, like profile, are inherited.
+		if f := rootCmd.Flags().Lookup(k.flag); f != nil {
+			configShowCmd.Flags().AddFlag(f)
+		}
+	}
+}
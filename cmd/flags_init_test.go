@@ -45,6 +45,22 @@ func TestInitFlags(t *testing.T) {
 		"format":          {},
 		"jobs":            {},
 		"sources":         {},
+
+		"read_timeout":         {},
+		"write_timeout":        {},
+		"idle_timeout":         {},
+		"shutdown_timeout":     {},
+		"verification_timeout": {},
+		"address":              {},
+		"base_path":            {},
+		"tls_cert":             {},
+		"tls_key":              {},
+		"tls_self_signed":      {},
+		"api_keys":             {},
+		"public_ui":            {},
+		"cors_origins":         {},
+		"cors_methods":         {},
+		"cors_headers":         {},
 	}
 
 	// Check that all expected flags exist
@@ -90,6 +106,15 @@ func TestWebFlags(t *testing.T) {
 	assert.Equal(t, "p", portFlag.Shorthand)
 	assert.Equal(t, "0", portFlag.DefValue)
 	assert.Equal(t, "Port to run web GUI.", portFlag.Usage)
+
+	for _, name := range []string{
+		"read_timeout", "write_timeout", "idle_timeout", "shutdown_timeout",
+	} {
+		flag := cmd.Flags().Lookup(name)
+		require.NotNil(t, flag, "Flag %s should exist", name)
+		assert.Equal(t, "", flag.Shorthand)
+		assert.Equal(t, "0s", flag.DefValue)
+	}
 }
 
 func TestVerificationFlags(t *testing.T) {
@@ -236,11 +261,12 @@ func TestDataSourcesFlags(t *testing.T) {
 	assert.Equal(t, "s", sourcesFlag.Shorthand)
 	assert.Equal(t, "", sourcesFlag.DefValue)
 
-	expectedUsage := `IDs of important data-sources to verify against (ex "1,11").
+	expectedUsage := `IDs or names of important data-sources to verify against (ex "1,11", "col,gbif").
   If sources are set and there are matches to their data,
   such matches are returned in "preferred_result" results.
   If the option is set to "0" all matched sources are returned.
 
+  Names are short titles or their acronyms, see "gnverifier sources".
   To find IDs refer to "https://verifier.globalnames.org/data_sources".
   1 - Catalogue of Life
   3 - ITIS
@@ -283,6 +309,22 @@ func TestFlagTypes(t *testing.T) {
 		"format":          "string",
 		"jobs":            "int",
 		"sources":         "string",
+
+		"read_timeout":         "duration",
+		"write_timeout":        "duration",
+		"idle_timeout":         "duration",
+		"shutdown_timeout":     "duration",
+		"verification_timeout": "duration",
+		"address":              "string",
+		"base_path":            "string",
+		"tls_cert":             "string",
+		"tls_key":              "string",
+		"tls_self_signed":      "bool",
+		"api_keys":             "string",
+		"public_ui":            "bool",
+		"cors_origins":         "string",
+		"cors_methods":         "string",
+		"cors_headers":         "string",
 	}
 
 	for flagName, expectedType := range flagTypes {
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gnames/gnfmt"
 	"github.com/gnames/gnverifier/pkg/config"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -342,6 +344,15 @@ func TestEnvironmentVariableIntegration(t *testing.T) {
 	})
 }
 
+func TestGetOptsNoVerificationTimeout(t *testing.T) {
+	defer viper.Reset()
+	opts = nil
+	viper.Set("VerificationTimeout", "0s")
+	getOpts()
+	cfg := config.New(opts...)
+	assert.Equal(t, time.Duration(0), cfg.VerificationTimeout)
+}
+
 func TestWebOptsIntegration(t *testing.T) {
 	t.Run("web options are properly configured", func(t *testing.T) {
 		// Reset global state
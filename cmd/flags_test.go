@@ -2,11 +2,16 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
 	"github.com/gnames/gnverifier/pkg/config"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -417,16 +422,10 @@ func TestSourcesFlag(t *testing.T) {
 			expectedSources: []int{1, 11, 180},
 		},
 		{
-			name:            "invalid sources",
-			sources:         "abc,def",
+			name:            "duplicate sources",
+			sources:         "1,11,1",
 			expectOpt:       true,
-			expectedSources: nil, // nil is still passed to OptDataSources
-		},
-		{
-			name:            "mixed valid and invalid sources",
-			sources:         "1,abc,180",
-			expectOpt:       true,
-			expectedSources: nil, // nil is still passed to OptDataSources
+			expectedSources: []int{1, 11},
 		},
 		{
 			name:            "negative source filtered out but positive ones kept",
@@ -507,6 +506,106 @@ func TestVerifierUrlFlag(t *testing.T) {
 	}
 }
 
+func TestVerificationTimeoutFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      string
+		value     time.Duration
+		expectOpt bool
+	}{
+		{"not set", "", 0, false},
+		{"custom timeout", "45s", 45 * time.Second, true},
+		{"no timeout", "0", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts = nil
+
+			cmd := &cobra.Command{}
+			cmd.Flags().Duration("verification_timeout", 0, "test flag")
+			if tt.flag != "" {
+				require.NoError(t, cmd.Flags().Set("verification_timeout", tt.flag))
+			}
+
+			verificationTimeoutFlag(cmd)
+
+			if tt.expectOpt {
+				assert.Len(t, opts, 1)
+				cfg := config.New(opts...)
+				assert.Equal(t, tt.value, cfg.VerificationTimeout)
+			} else {
+				assert.Len(t, opts, 0)
+			}
+		})
+	}
+}
+
+func TestWebTimeoutsFlag(t *testing.T) {
+	opts = nil
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Duration("read_timeout", time.Minute, "test flag")
+	cmd.Flags().Duration("write_timeout", 2*time.Minute, "test flag")
+	cmd.Flags().Duration("idle_timeout", 0, "test flag")
+	cmd.Flags().Duration("shutdown_timeout", 10*time.Second, "test flag")
+
+	webTimeoutsFlag(cmd)
+
+	assert.Len(t, opts, 3)
+	cfg := config.New(opts...)
+	assert.Equal(t, time.Minute, cfg.WebReadTimeout)
+	assert.Equal(t, 2*time.Minute, cfg.WebWriteTimeout)
+	assert.Equal(t, 2*time.Minute, cfg.WebIdleTimeout)
+	assert.Equal(t, 10*time.Second, cfg.WebShutdownTimeout)
+}
+
+func TestWebServerFlags(t *testing.T) {
+	opts = nil
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("address", "127.0.0.1", "test flag")
+	cmd.Flags().String("base_path", "/tools/gnverifier/", "test flag")
+	cmd.Flags().String("tls_cert", "cert.pem", "test flag")
+	cmd.Flags().String("tls_key", "key.pem", "test flag")
+	cmd.Flags().Bool("tls_self_signed", false, "test flag")
+
+	cmd.Flags().String("api_keys", "keys.yaml", "test flag")
+	cmd.Flags().Bool("public_ui", true, "test flag")
+
+	addressFlag(cmd)
+	basePathFlag(cmd)
+	tlsFlag(cmd)
+	apiKeysFlag(cmd)
+
+	assert.Len(t, opts, 6)
+	cfg := config.New(opts...)
+	assert.Equal(t, "127.0.0.1", cfg.WebAddress)
+	assert.Equal(t, "/tools/gnverifier", cfg.WebBasePath)
+	assert.Equal(t, "cert.pem", cfg.WebTLSCert)
+	assert.Equal(t, "key.pem", cfg.WebTLSKey)
+	assert.False(t, cfg.WebTLSSelfSigned)
+	assert.Equal(t, "keys.yaml", cfg.WebAPIKeysFile)
+	assert.True(t, cfg.WebPublicUI)
+}
+
+func TestCorsFlag(t *testing.T) {
+	opts = nil
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("cors_origins", "https://a.org, https://b.org", "test flag")
+	cmd.Flags().String("cors_methods", "", "test flag")
+	cmd.Flags().String("cors_headers", "X-API-Key,,Content-Type", "test flag")
+
+	corsFlag(cmd)
+
+	assert.Len(t, opts, 2)
+	cfg := config.New(opts...)
+	assert.Equal(t, []string{"https://a.org", "https://b.org"}, cfg.WebCORSOrigins)
+	assert.Empty(t, cfg.WebCORSMethods)
+	assert.Equal(t, []string{"X-API-Key", "Content-Type"}, cfg.WebCORSHeaders)
+}
+
 func TestVernacularsFlag(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -579,10 +678,27 @@ func TestVernacularsFlag(t *testing.T) {
 }
 
 func TestParseDataSources(t *testing.T) {
+	dss := []vlib.DataSource{
+		{ID: 1, Title: "Catalogue of Life", TitleShort: "Catalogue of Life"},
+		{ID: 3, Title: "Integrated Taxonomic Information SystemITIS",
+			TitleShort: "ITIS"},
+		{ID: 11, Title: "GBIF Backbone Taxonomy",
+			TitleShort: "GBIF Backbone Taxonomy"},
+		{ID: 12, Title: "Encyclopedia of Life", TitleShort: "EOL"},
+		{ID: 181, Title: "The Interim Register of Marine and Nonmarine Genera",
+			TitleShort: "IRMNG (old)"},
+		{ID: 182, Title: "The Interim Register of Marine and Nonmarine Genera",
+			TitleShort: "IRMNG"},
+		{ID: 200, Title: "Test One", TitleShort: "Test One"},
+		{ID: 201, Title: "Test Two", TitleShort: "Test Two"},
+		{ID: 202, Title: "No Short Title", TitleShort: " "},
+	}
+
 	tests := []struct {
 		name     string
 		input    string
 		expected []int
+		hasErr   bool
 	}{
 		{
 			name:     "empty string",
@@ -604,16 +720,6 @@ func TestParseDataSources(t *testing.T) {
 			input:    " 1 , 11 , 180 ",
 			expected: []int{1, 11, 180},
 		},
-		{
-			name:     "invalid source",
-			input:    "abc",
-			expected: nil,
-		},
-		{
-			name:     "mixed valid and invalid",
-			input:    "1,abc,180",
-			expected: nil,
-		},
 		{
 			name:     "negative sources filtered",
 			input:    "1,-5,180",
@@ -624,6 +730,31 @@ func TestParseDataSources(t *testing.T) {
 			input:    "-1,-5,-10",
 			expected: nil,
 		},
+		{
+			name:     "short titles and acronyms",
+			input:    "col,itis,gbif,EOL",
+			expected: []int{1, 3, 11, 12},
+		},
+		{
+			name:     "names and IDs",
+			input:    "catalogue of life, 180, irmng",
+			expected: []int{1, 180, 182},
+		},
+		{
+			name:     "alias",
+			input:    "my flora,1",
+			expected: []int{208, 1},
+		},
+		{
+			name:   "unknown name",
+			input:  "1,abc,180",
+			hasErr: true,
+		},
+		{
+			name:   "ambiguous name",
+			input:  "test",
+			hasErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -633,12 +764,81 @@ func TestParseDataSources(t *testing.T) {
 			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
 			slog.SetDefault(logger)
 
-			result := parseDataSources(tt.input)
+			r := &sourceResolver{
+				aliases:   map[string]int{"My Flora": 208},
+				cachePath: filepath.Join(t.TempDir(), "data_sources.json"),
+				fetch:     func() ([]vlib.DataSource, error) { return dss, nil },
+			}
+			result, err := parseDataSources(tt.input, r)
+			if tt.hasErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+func TestSourceResolverCache(t *testing.T) {
+	dss := []vlib.DataSource{{ID: 1, TitleShort: "Catalogue of Life"}}
+	path := filepath.Join(t.TempDir(), "gnverifier", "data_sources.json")
+	var calls int
+
+	r := &sourceResolver{
+		cachePath: path,
+		fetch: func() ([]vlib.DataSource, error) {
+			calls++
+			return dss, nil
+		},
+	}
+	id, err := r.resolve("col")
+	require.NoError(t, err)
+	assert.Equal(t, 1, id)
+	assert.Equal(t, 1, calls)
+	assert.FileExists(t, path)
+
+	// the service is unavailable, fresh cache is used
+	r = &sourceResolver{
+		cachePath: path,
+		fetch: func() ([]vlib.DataSource, error) {
+			return nil, errors.New("offline")
+		},
+	}
+	id, err = r.resolve("Catalogue of Life")
+	require.NoError(t, err)
+	assert.Equal(t, 1, id)
+
+	// stale cache is used when the service is unavailable
+	old := time.Now().Add(-2 * sourcesCacheAge)
+	require.NoError(t, os.Chtimes(path, old, old))
+	r = &sourceResolver{
+		cachePath: path,
+		fetch: func() ([]vlib.DataSource, error) {
+			return nil, errors.New("offline")
+		},
+	}
+	id, err = r.resolve("col")
+	require.NoError(t, err)
+	assert.Equal(t, 1, id)
+
+	// unknown name refreshes cached list
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now()))
+	dss = append(dss, vlib.DataSource{ID: 3, TitleShort: "ITIS"})
+	calls = 0
+	r = &sourceResolver{
+		cachePath: path,
+		fetch: func() ([]vlib.DataSource, error) {
+			calls++
+			return dss, nil
+		},
+	}
+	id, err = r.resolve("itis")
+	require.NoError(t, err)
+	assert.Equal(t, 3, id)
+	assert.Equal(t, 1, calls)
+}
+
 func TestParseVernacularLanguages(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -709,6 +909,13 @@ func TestAllFlagFunctions(t *testing.T) {
 		vernacularsFlag,
 		verifierUrlFlag,
 		quietFlag,
+		verificationTimeoutFlag,
+		webTimeoutsFlag,
+		addressFlag,
+		basePathFlag,
+		tlsFlag,
+		apiKeysFlag,
+		corsFlag,
 	}
 
 	// Reset global state
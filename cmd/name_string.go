@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnsys"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/io/verifrest"
+	"github.com/spf13/cobra"
+)
+
+// nameStringCmd finds verification data by name-string UUIDs.
+var nameStringCmd = &cobra.Command{
+	Use:   "name-string [uuid|name-string|file]...",
+	Short: "Finds verification data by UUIDs of name-strings.",
+	Long: `Finds verification data by UUIDs of name-strings, or by
+name-strings themselves. Takes one or more UUIDs as arguments, a file with
+one UUID per line, or reads UUIDs from standard input. Files and standard
+input are processed in parallel.
+
+  examples:
+    gnverifier name-string 0b5a4d9b-5a0f-5a2c-8a5e-6d3ea1b6c1d2
+    gnverifier name-string "Bubo bubo" -s "1,11" -f pretty
+    gnverifier name-string uuids.txt -j 8 > results.csv
+    cat uuids.txt | gnverifier name-string`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := []funcFlag{
+			formatFlag, jobsFlag, allMatchesFlag, verifierUrlFlag,
+			sourcesFlag, quietFlag,
+		}
+		for _, f := range flags {
+			f(cmd)
+		}
+
+		cfg := config.New(opts...)
+		vfr := verifrest.New(cfg.VerifierURL)
+		gnv := gnverifier.New(cfg, vfr)
+
+		if len(args) == 0 {
+			if !checkStdin() {
+				_ = cmd.Help()
+				return
+			}
+			nameStringsFile(gnv, os.Stdin)
+			return
+		}
+
+		if len(args) == 1 {
+			if exists, _ := gnsys.FileExists(args[0]); exists {
+				f, err := os.Open(args[0])
+				if err != nil {
+					slog.Error("Cannot open file", "error", err, "file", args[0])
+					os.Exit(1)
+				}
+				defer f.Close()
+				nameStringsFile(gnv, f)
+				return
+			}
+		}
+
+		printNameStrings(gnv, nameStrings(gnv, args), true)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nameStringCmd)
+	fs := nameStringCmd.Flags()
+	fs.StringP("format", "f", "", `Format of the output: "compact", "pretty", "csv", "tsv".`)
+	fs.StringP("sources", "s", "",
+		`IDs or names of data-sources to return results from (ex "1,11", "col").`)
+	fs.BoolP("all_matches", "M", false, "return all matched results per source.")
+	fs.IntP("jobs", "j", 4, "Number of lookups running in parallel.")
+	fs.StringP("verifier_url", "v", "", "URL for verification service.")
+	fs.BoolP("quiet", "q", false, "do not show progress")
+}
+
+// nameStringsFile looks up UUIDs from a reader, one per line. Lines are
+// processed in batches, lookups inside a batch run in parallel.
+func nameStringsFile(gnv gnverifier.GNverifier, r io.Reader) {
+	var count int
+	readBatches(r, gnv.Config().Batch, func(ids []string, first bool) {
+		printNameStrings(gnv, nameStrings(gnv, ids), first)
+		count += len(ids)
+		slog.Info("Looked up name-strings.", "names", count)
+	})
+}
+
+// nameStrings looks up all ids using Jobs parallel workers. The results
+// keep the order of ids.
+func nameStrings(gnv gnverifier.GNverifier, ids []string) []vlib.Name {
+	return parallelMap(gnv.Config().Jobs, ids, func(id string) vlib.Name {
+		return nameString(gnv, id)
+	})
+}
+
+// nameString looks up one UUID or name-string. If nothing is found, or
+// the lookup failed, the result has NoMatch type.
+func nameString(gnv gnverifier.GNverifier, id string) vlib.Name {
+	cfg := gnv.Config()
+	inp := vlib.NameStringInput{
+		ID:             id,
+		DataSources:    cfg.DataSources,
+		WithAllMatches: cfg.WithAllMatches,
+	}
+	out, err := gnv.NameString(context.Background(), inp)
+	if err == nil && out.Name != nil {
+		return *out.Name
+	}
+
+	res := vlib.Name{Name: id, MatchType: vlib.NoMatch}
+	if err != nil {
+		res.Error = fmt.Sprintf("cannot get name-string: %s", err)
+	}
+	return res
+}
+
+func printNameStrings(
+	gnv gnverifier.GNverifier,
+	names []vlib.Name,
+	header bool,
+) {
+	f := gnv.Config().Format
+	if header && (f == gnfmt.CSV || f == gnfmt.TSV) {
+		fmt.Println(output.CSVHeader(f))
+	}
+	for _, v := range names {
+		if v.Error != "" {
+			slog.Error("Error during name-string lookup", "error", v.Error)
+		}
+		fmt.Println(output.NameOutput(v, f))
+	}
+}
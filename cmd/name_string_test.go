@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	vtest "github.com/gnames/gnverifier/pkg/ent/verifier/verifiertesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameStrings(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.NameStringCalls(func(
+		_ context.Context,
+		inp vlib.NameStringInput,
+	) (vlib.NameStringOutput, error) {
+		switch inp.ID {
+		case "missing":
+			return vlib.NameStringOutput{}, nil
+		case "broken":
+			return vlib.NameStringOutput{}, errors.New("timeout")
+		}
+		name := vlib.Name{Name: inp.ID, MatchType: vlib.Exact}
+		return vlib.NameStringOutput{Name: &name}, nil
+	})
+	cfg := config.New(
+		config.OptJobs(3),
+		config.OptDataSources([]int{1, 11}),
+		config.OptWithAllMatches(true),
+	)
+	gnv := gnverifier.New(cfg, vfr)
+
+	ids := []string{"a", "missing", "b", "broken", "c", "d", "e"}
+	res := nameStrings(gnv, ids)
+	require.Len(t, res, len(ids))
+	for i := range ids {
+		assert.Equal(t, ids[i], res[i].Name)
+	}
+	assert.Equal(t, vlib.Exact, res[0].MatchType)
+	assert.Equal(t, vlib.NoMatch, res[1].MatchType)
+	assert.Empty(t, res[1].Error)
+	assert.Equal(t, vlib.NoMatch, res[3].MatchType)
+	assert.Contains(t, res[3].Error, "timeout")
+
+	assert.Equal(t, len(ids), vfr.NameStringCallCount())
+	_, inp := vfr.NameStringArgsForCall(0)
+	assert.Equal(t, []int{1, 11}, inp.DataSources)
+	assert.True(t, inp.WithAllMatches)
+}
+
+func TestNameStringCommand(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"name-string"})
+	require.NoError(t, err)
+	assert.Equal(t, "name-string", cmd.Name())
+	flags := []string{
+		"format", "sources", "all_matches", "jobs", "verifier_url", "quiet",
+	}
+	for _, v := range flags {
+		assert.NotNil(t, cmd.Flags().Lookup(v), v)
+	}
+}
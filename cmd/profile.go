@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/spf13/viper"
+)
+
+// profile is the name of the profile used by the current run. It is
+// empty if no profile is selected.
+var profile string
+
+// knownProfiles are names of profiles from the configuration file.
+var knownProfiles []string
+
+// applyProfile layers settings of a profile from the Profiles section of
+// the configuration file over the base settings. Settings given by
+// environment variables keep priority over the profile.
+func applyProfile(v *viper.Viper, cfg *cfgData, name string) error {
+	name = strings.ToLower(name)
+	if _, ok := cfg.Profiles[name]; !ok {
+		return fmt.Errorf(
+			"unknown profile '%s', known profiles: %s", name,
+			strings.Join(profileNames(cfg), ", "),
+		)
+	}
+	sub := v.Sub("profiles." + name)
+	if sub == nil {
+		// the profile has no settings.
+		return nil
+	}
+	cv := reflect.ValueOf(cfg).Elem()
+	for _, k := range configKeys {
+		if !sub.IsSet(k.name) {
+			continue
+		}
+		// values of the profile replace base values, lists are not merged.
+		f := cv.FieldByName(k.name)
+		f.Set(reflect.Zero(f.Type()))
+		if _, ok := os.LookupEnv(k.env); k.env != "" && ok {
+			sub.Set(k.name, v.Get(k.name))
+		}
+	}
+	return sub.Unmarshal(cfg)
+}
+
+// profileNames returns sorted names of profiles from the configuration
+// file.
+func profileNames(cfg *cfgData) []string {
+	return slices.Sorted(maps.Keys(cfg.Profiles))
+}
+
+// webPresets converts profiles to presets of the web GUI form. Every
+// preset is a profile layered over the base settings.
+func webPresets(v *viper.Viper, base cfgData) []config.Preset {
+	var res []config.Preset
+	for _, name := range profileNames(&base) {
+		cfg := base
+		if err := applyProfile(v, &cfg, name); err != nil {
+			continue
+		}
+		res = append(res, config.Preset{
+			Name:                    name,
+			DataSources:             cfg.DataSources,
+			Vernaculars:             cfg.Vernaculars,
+			WithAllMatches:          cfg.WithAllMatches,
+			WithSpeciesGroup:        cfg.WithSpeciesGroup,
+			WithRelaxedFuzzyMatch:   cfg.WithRelaxedFuzzyMatch,
+			WithUninomialFuzzyMatch: cfg.WithUninomialFuzzyMatch,
+		})
+	}
+	return res
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesYAML = `DataSources: [1, 11, 12]
+WithAllMatches: true
+Jobs: 8
+Profiles:
+  marine:
+    DataSources: [9]
+    WithRelaxedFuzzyMatch: true
+  Botany:
+    DataSources: [196, 167]
+    WithSpeciesGroup: true
+    WithAllMatches: false
+    Jobs: 2
+`
+
+func profilesViper(t *testing.T, txt string) *viper.Viper {
+	path := filepath.Join(t.TempDir(), "gnverifier.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(txt), 0o644))
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	return v
+}
+
+func TestApplyProfile(t *testing.T) {
+	v := profilesViper(t, profilesYAML)
+	base := cfgData{}
+	require.NoError(t, v.Unmarshal(&base))
+	assert.Equal(t, []string{"botany", "marine"}, profileNames(&base))
+
+	cfg := base
+	require.NoError(t, applyProfile(v, &cfg, "marine"))
+	assert.Equal(t, []int{9}, cfg.DataSources)
+	assert.True(t, cfg.WithRelaxedFuzzyMatch)
+	assert.True(t, cfg.WithAllMatches)
+	assert.Equal(t, 8, cfg.Jobs)
+	// the base settings are not changed.
+	assert.Equal(t, []int{1, 11, 12}, base.DataSources)
+
+	cfg = base
+	require.NoError(t, applyProfile(v, &cfg, "BOTANY"))
+	assert.Equal(t, []int{196, 167}, cfg.DataSources)
+	assert.True(t, cfg.WithSpeciesGroup)
+	assert.False(t, cfg.WithAllMatches)
+	assert.Equal(t, 2, cfg.Jobs)
+
+	cfg = base
+	err := applyProfile(v, &cfg, "birds")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "botany, marine")
+}
+
+func TestApplyProfileEnv(t *testing.T) {
+	t.Setenv("GNV_JOBS", "16")
+	v := profilesViper(t, profilesYAML)
+	_ = v.BindEnv("Jobs", "GNV_JOBS")
+	cfg := cfgData{}
+	require.NoError(t, v.Unmarshal(&cfg))
+	require.NoError(t, applyProfile(v, &cfg, "botany"))
+	assert.Equal(t, 16, cfg.Jobs)
+	assert.Equal(t, []int{196, 167}, cfg.DataSources)
+}
+
+func TestWebPresets(t *testing.T) {
+	v := profilesViper(t, profilesYAML)
+	base := cfgData{}
+	require.NoError(t, v.Unmarshal(&base))
+	ps := webPresets(v, base)
+	require.Len(t, ps, 2)
+	assert.Equal(t, "botany", ps[0].Name)
+	assert.Equal(t, []int{196, 167}, ps[0].DataSources)
+	assert.True(t, ps[0].WithSpeciesGroup)
+	assert.Equal(t, "marine", ps[1].Name)
+	assert.True(t, ps[1].WithAllMatches)
+	assert.True(t, ps[1].WithRelaxedFuzzyMatch)
+}
+
+func TestValidateProfiles(t *testing.T) {
+	v := profilesViper(t, profilesYAML)
+	assert.Empty(t, validateConfig(v))
+
+	txt := profilesYAML + `  birds:
+    Fromat: tsv
+    Jobs: -1
+Profile: fungi
+`
+	v = profilesViper(t, txt)
+	errs := validateConfig(v)
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "profiles.birds.fromat")
+	assert.Contains(t, errs[1].Error(), "fungi")
+	assert.Contains(t, errs[2].Error(), "profile 'birds'")
+}
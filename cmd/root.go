@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -22,7 +23,10 @@ import (
 	"github.com/gnames/gnsys"
 	gnverifier "github.com/gnames/gnverifier/pkg"
 	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/clean"
 	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/ent/resolve"
+	"github.com/gnames/gnverifier/pkg/ent/within"
 	"github.com/gnames/gnverifier/pkg/io/verifrest"
 	"github.com/gnames/gnverifier/pkg/io/web"
 	"github.com/spf13/cobra"
@@ -35,19 +39,55 @@ var configText string
 var (
 	opts, webOpts []config.Option
 	msg           []string
+
+	// cfgPort, cfgQuiet and cfgDecisionsFile are settings from the config
+	// file or environment that are not part of config.Config. Flags
+	// override them.
+	cfgPort          int
+	cfgQuiet         bool
+	cfgDecisionsFile string
 )
 
 // cfgData purpose is to achieve automatic import of data from the
 // configuration file, if it exists.
 type cfgData struct {
+	Batch                   int
+	Cleaning                []string
+	DataSourceAliases       map[string]int
 	DataSources             []int
+	DecisionsFile           string
+	Dedup                   string
 	Format                  string
 	Jobs                    int
+	NamesNumThreshold       int
+	Port                    int
+	Profile                 string
+	Profiles                map[string]map[string]any
+	Quiet                   bool
+	Resolve                 bool
+	VerificationTimeout     time.Duration
 	VerifierURL             string
+	Vernaculars             []string
+	WebAddress              string
+	WebAPIKeysFile          string
+	WebBasePath             string
+	WebCORSHeaders          []string
+	WebCORSMethods          []string
+	WebCORSOrigins          []string
+	WebIdleTimeout          time.Duration
+	WebPublicUI             bool
+	WebReadTimeout          time.Duration
+	WebShutdownTimeout      time.Duration
+	WebTLSCert              string
+	WebTLSKey               string
+	WebTLSSelfSigned        bool
+	WebWriteTimeout         time.Duration
 	WithAllMatches          bool
 	WithCapitalization      bool
+	WithRelaxedFuzzyMatch   bool
 	WithSpeciesGroup        bool
 	WithUninomialFuzzyMatch bool
+	Within                  string
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -62,7 +102,10 @@ https://github.com/gnames/gnverifier
     gnverifier "Pardosa moesta"
     gnverifier file_with_names.txt
     gnverifier "g:M. sp:galloprovincialis au:Oliv."
+    gnverifier sources
 `,
+	// names and files are positional arguments, they are not subcommands.
+	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		// if there is version flag, show version and exit
 		versionFlag(cmd)
@@ -70,7 +113,10 @@ https://github.com/gnames/gnverifier
 		flags := []funcFlag{
 			capitalizeFlag, spGroupFlag, fuzzyRelaxedFlag,
 			fuzzyUninomialFlag, formatFlag, jobsFlag, allMatchesFlag,
-			sourcesFlag, vernacularsFlag, verifierUrlFlag, quietFlag,
+			verifierUrlFlag, sourcesFlag, vernacularsFlag, quietFlag, dedupFlag,
+			cleanFlag, resolveFlag, withinFlag, decisionsFlag,
+			verificationTimeoutFlag, webTimeoutsFlag, addressFlag,
+			basePathFlag, tlsFlag, apiKeysFlag, corsFlag,
 		}
 
 		for _, f := range flags {
@@ -83,7 +129,7 @@ https://github.com/gnames/gnverifier
 		}
 
 		// if port is given run gnverifier web UI instead
-		port, _ := cmd.Flags().GetInt("port")
+		port := webPort(cmd)
 		if port > 0 {
 			// Copy opts to webOpts after flags have been processed
 			webOpts = append([]config.Option{}, opts...)
@@ -91,7 +137,11 @@ https://github.com/gnames/gnverifier
 			cfg := config.New(webOpts...)
 			vfr := verifrest.New(cfg.VerifierURL)
 			gnv := gnverifier.New(cfg, vfr)
-			web.Run(gnv, port)
+			err := web.Run(gnv, port)
+			if err != nil {
+				slog.Error("Web server failed", "error", err)
+				os.Exit(1)
+			}
 			os.Exit(0)
 		}
 
@@ -120,6 +170,7 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(initConfig)
 	initFlags()
+	configShowFlags()
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -141,18 +192,19 @@ func initConfig() {
 
 	// Set environment variables to override
 	// config file settings
-	_ = viper.BindEnv("DataSources", "GNV_DATA_SOURCES")
-	_ = viper.BindEnv("Format", "GNV_FORMAT")
-	_ = viper.BindEnv("Jobs", "GNV_JOBS")
-	_ = viper.BindEnv("VerifierURL", "GNV_VERIFIER_URL")
-	_ = viper.BindEnv("WithAllMatches", "GNV_WITH_ALL_MATCHES")
-	_ = viper.BindEnv("WithCapitalization", "GNV_WITH_CAPITALIZATION")
-	_ = viper.BindEnv("WithSpeciesGroup", "GNV_WITH_SPECIES_GROUP")
+	for _, k := range configKeys {
+		// aliases are a map, getOpts reads them from environment.
+		if k.env != "" && k.name != "DataSourceAliases" {
+			_ = viper.BindEnv(k.name, k.env)
+		}
+	}
+
+	_ = viper.BindPFlag("Profile", rootCmd.PersistentFlags().Lookup("profile"))
 
 	viper.AutomaticEnv() // read in environment variables that match
 
-	configPath := filepath.Join(configDir, fmt.Sprintf("%s.yaml", configFile))
-	touchConfigFile(configPath)
+	configFilePath = filepath.Join(configDir, fmt.Sprintf("%s.yaml", configFile))
+	touchConfigFile(configFilePath)
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
@@ -170,35 +222,149 @@ func getOpts() {
 	if err != nil {
 		slog.Error("Cannot deserialize config data", "error", err)
 	}
+	for _, k := range unknownConfigKeys(viper.GetViper()) {
+		slog.Warn("Unknown key in config file is ignored", "key", k)
+	}
+
+	knownProfiles = profileNames(cfg)
+	if len(knownProfiles) > 0 {
+		presets := webPresets(viper.GetViper(), *cfg)
+		opts = append(opts, config.OptWebPresets(presets))
+	}
+	if cfg.Profile != "" {
+		profile = strings.ToLower(cfg.Profile)
+		if err = applyProfile(viper.GetViper(), cfg, profile); err != nil {
+			slog.Error("Cannot use profile", "error", err)
+			os.Exit(1)
+		}
+		msg = append(msg, fmt.Sprintf("Using profile '%s'.", profile))
+	}
 
+	if cfg.Batch > 0 {
+		opts = append(opts, config.OptBatch(cfg.Batch))
+	}
+
+	if s, ok := os.LookupEnv("GNV_DATA_SOURCE_ALIASES"); ok {
+		k, _ := findConfigKey("DataSourceAliases")
+		aliases, err := parseConfigValue(k, s)
+		if err != nil {
+			slog.Error("Cannot use GNV_DATA_SOURCE_ALIASES", "error", err)
+			os.Exit(1)
+		}
+		cfg.DataSourceAliases = aliases.(map[string]int)
+	}
+	if len(cfg.DataSourceAliases) > 0 {
+		sourceAliases = cfg.DataSourceAliases
+	}
+	cfgPort = cfg.Port
+	cfgQuiet = cfg.Quiet
+	cfgDecisionsFile = cfg.DecisionsFile
 	if len(cfg.DataSources) > 0 {
 		opts = append(opts, config.OptDataSources(cfg.DataSources))
 	}
+	if len(cfg.Cleaning) > 0 {
+		steps, err := clean.ParseSteps(cfg.Cleaning)
+		if err != nil {
+			slog.Error("Cannot use Cleaning", "error", err)
+			os.Exit(1)
+		}
+		opts = append(opts, config.OptCleaning(steps))
+	}
+	if cfg.Dedup != "" {
+		dedup, err := config.NewDedupMode(cfg.Dedup)
+		if err != nil {
+			slog.Error("Cannot use Dedup", "error", err)
+			os.Exit(1)
+		}
+		opts = append(opts, config.OptDedup(dedup))
+	}
 	if cfg.Format != "" {
 		cfgFormat, err := gnfmt.NewFormat(cfg.Format)
 		if err != nil {
-			cfgFormat = gnfmt.CSV
+			slog.Error("Cannot use Format", "error", err)
+			os.Exit(1)
 		}
 		opts = append(opts, config.OptFormat(cfgFormat))
 	}
 	if cfg.Jobs > 0 {
 		opts = append(opts, config.OptJobs(cfg.Jobs))
 	}
+	if cfg.NamesNumThreshold > 0 {
+		opts = append(opts, config.OptNamesNumThreshold(cfg.NamesNumThreshold))
+	}
+	// zero is a valid value, it turns the timeout off.
+	if cfg.VerificationTimeout > 0 || viper.IsSet("VerificationTimeout") {
+		opts = append(opts, config.OptVerificationTimeout(cfg.VerificationTimeout))
+	}
 	if cfg.VerifierURL != "" {
 		opts = append(opts, config.OptVerifierURL(cfg.VerifierURL))
 	}
+	if len(cfg.Vernaculars) > 0 {
+		opts = append(opts, config.OptVernaculars(cfg.Vernaculars))
+	}
+	if cfg.WebAddress != "" {
+		opts = append(opts, config.OptWebAddress(cfg.WebAddress))
+	}
+	if cfg.WebAPIKeysFile != "" {
+		opts = append(opts, config.OptWebAPIKeysFile(cfg.WebAPIKeysFile))
+	}
+	if cfg.WebBasePath != "" {
+		opts = append(opts, config.OptWebBasePath(cfg.WebBasePath))
+	}
+	if len(cfg.WebCORSHeaders) > 0 {
+		opts = append(opts, config.OptWebCORSHeaders(cfg.WebCORSHeaders))
+	}
+	if len(cfg.WebCORSMethods) > 0 {
+		opts = append(opts, config.OptWebCORSMethods(cfg.WebCORSMethods))
+	}
+	if len(cfg.WebCORSOrigins) > 0 {
+		opts = append(opts, config.OptWebCORSOrigins(cfg.WebCORSOrigins))
+	}
+	if cfg.WebIdleTimeout > 0 {
+		opts = append(opts, config.OptWebIdleTimeout(cfg.WebIdleTimeout))
+	}
+	if cfg.WebPublicUI {
+		opts = append(opts, config.OptWebPublicUI(true))
+	}
+	if cfg.WebReadTimeout > 0 {
+		opts = append(opts, config.OptWebReadTimeout(cfg.WebReadTimeout))
+	}
+	if cfg.WebShutdownTimeout > 0 {
+		opts = append(opts, config.OptWebShutdownTimeout(cfg.WebShutdownTimeout))
+	}
+	if cfg.WebTLSCert != "" {
+		opts = append(opts, config.OptWebTLSCert(cfg.WebTLSCert))
+	}
+	if cfg.WebTLSKey != "" {
+		opts = append(opts, config.OptWebTLSKey(cfg.WebTLSKey))
+	}
+	if cfg.WebTLSSelfSigned {
+		opts = append(opts, config.OptWebTLSSelfSigned(true))
+	}
+	if cfg.WebWriteTimeout > 0 {
+		opts = append(opts, config.OptWebWriteTimeout(cfg.WebWriteTimeout))
+	}
+	if cfg.Resolve {
+		opts = append(opts, config.OptResolve(true))
+	}
 	if cfg.WithAllMatches {
 		opts = append(opts, config.OptWithAllMatches(true))
 	}
 	if cfg.WithCapitalization {
 		opts = append(opts, config.OptWithCapitalization(true))
 	}
+	if cfg.WithRelaxedFuzzyMatch {
+		opts = append(opts, config.OptWithRelaxedFuzzyMatch(true))
+	}
 	if cfg.WithSpeciesGroup {
 		opts = append(opts, config.OptWithSpeciesGroup(true))
 	}
 	if cfg.WithUninomialFuzzyMatch {
 		opts = append(opts, config.OptWithUninomialFuzzyMatch(true))
 	}
+	if cfg.Within != "" {
+		opts = append(opts, config.OptWithin(cfg.Within))
+	}
 }
 
 func processStdin(
@@ -211,7 +377,9 @@ func processStdin(
 	}
 	vfr := verifrest.New(cfg.VerifierURL)
 	gnv := gnverifier.New(cfg, vfr)
-	verifyFile(gnv, os.Stdin)
+	if err := verifyFile(context.Background(), gnv, os.Stdin, os.Stdout); err != nil {
+		slog.Error("Verification failed for some names", "error", err)
+	}
 }
 
 func checkStdin() bool {
@@ -242,7 +410,9 @@ func verify(gnv gnverifier.GNverifier, str string) {
 		if err != nil {
 			slog.Error("Cannot open file", "error", err, "file", str)
 		}
-		verifyFile(gnv, f)
+		if err = verifyFile(context.Background(), gnv, f, os.Stdout); err != nil {
+			slog.Error("Verification failed for some names", "error", err)
+		}
 		f.Close()
 	} else if search.IsQuery(str) {
 		searchQuery(gnv, str)
@@ -251,79 +421,200 @@ func verify(gnv gnverifier.GNverifier, str string) {
 	}
 }
 
-func verifyFile(gnv gnverifier.GNverifier, f io.Reader) {
-	batch := gnv.Config().Batch
-	in := make(chan []string)
-	out := make(chan []vlib.Name)
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go gnv.VerifyStream(context.Background(), in, out)
-	go processResults(gnv, out, &wg)
-	sc := bufio.NewScanner(f)
-	names := make([]string, 0, batch)
-	for sc.Scan() {
-		name := sc.Text()
-		names = append(names, strings.Trim(name, " "))
-		if len(names) == batch {
-			in <- names
-			names = make([]string, 0, batch)
+// verifyFile verifies names from a reader, one name per line, and writes
+// results to a writer in the order of the input. It returns an error if
+// some names could not be verified, lines of such names are logged.
+func verifyFile(
+	ctx context.Context,
+	gnv gnverifier.GNverifier,
+	f io.Reader,
+	w io.Writer,
+) error {
+	cfg := gnv.Config()
+	cl := clean.New(cfg.Cleaning...)
+	// inputs keeps original strings of names that are being verified.
+	inputs := &inputQueue{}
+	names := func(yield func(string) bool) {
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			name := strings.Trim(sc.Text(), " ")
+			inputs.push(name)
+			if !yield(name) {
+				return
+			}
 		}
 	}
-	in <- names
-	close(in)
-	wg.Wait()
-}
 
-func processResults(gnv gnverifier.GNverifier, out <-chan []vlib.Name,
-	wg *sync.WaitGroup) {
-	defer wg.Done()
-	timeStart := time.Now().UnixNano()
-	f := gnv.Config().Format
-	if f == gnfmt.CSV || f == gnfmt.TSV {
-		fmt.Println(output.CSVHeader(f))
+	if cfg.Format == gnfmt.CSV || cfg.Format == gnfmt.TSV {
+		fmt.Fprintln(w, outputHeader(cfg, cl))
 	}
+
+	var res error
 	var count int
-	for o := range out {
+	var failed lineRanges
+	timeStart := time.Now()
+	for r, err := range gnv.VerifyIter(ctx, names) {
+		if err != nil && res == nil {
+			res = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if r.Error != "" {
+			slog.Error("Error during verification", "error", r.Error)
+			failed.add(count + 1)
+		}
+		warnOutside(cfg, r)
+		fmt.Fprintln(w, nameOutput(gnv, cl, inputs.pop(), r))
 		count++
-		total := int64(count * len(o))
-		timeSpent := float64(time.Now().UnixNano()-timeStart) / 1_000_000_000
-		speed := int64(float64(total) / timeSpent)
-
-		slog.Info("Verified.",
-			"names/sec", humanize.Comma(speed),
-			"names", humanize.Comma(int64(total)),
-		)
-		for _, r := range o {
-			if r.Error != "" {
-				slog.Error("Error during verification", "error", r.Error)
-			}
-			fmt.Println(output.NameOutput(r, f))
+		if count%cfg.Batch == 0 {
+			logProgress(count, timeStart)
 		}
 	}
+	if count%cfg.Batch != 0 {
+		logProgress(count, timeStart)
+	}
+	if len(failed) > 0 {
+		slog.Error("Some names were not verified", "lines", failed.String())
+	}
+	return res
+}
+
+// lineRanges are numbers of lines collected into ranges, for example
+// "3-5, 9".
+type lineRanges [][2]int
+
+func (lr *lineRanges) add(line int) {
+	if n := len(*lr); n > 0 && (*lr)[n-1][1] == line-1 {
+		(*lr)[n-1][1] = line
+		return
+	}
+	*lr = append(*lr, [2]int{line, line})
+}
+
+func (lr lineRanges) String() string {
+	res := make([]string, len(lr))
+	for i, v := range lr {
+		res[i] = strconv.Itoa(v[0])
+		if v[1] > v[0] {
+			res[i] += "-" + strconv.Itoa(v[1])
+		}
+	}
+	return strings.Join(res, ", ")
+}
+
+// outputColumns returns optional columns of the output. The Within
+// column is about the best result, so it is absent in resolve mode.
+func outputColumns(cfg config.Config, cl clean.Cleaner) output.Columns {
+	return output.Columns{
+		Input:    cl.Enabled(),
+		Within:   cfg.Within != "" && !cfg.Resolve,
+		Decision: len(cfg.Decisions) > 0,
+	}
+}
+
+// outputHeader returns the CSV header that corresponds to nameOutput.
+func outputHeader(cfg config.Config, cl clean.Cleaner) string {
+	if cfg.Resolve {
+		return output.ResolutionHeader(cfg.Format, outputColumns(cfg, cl))
+	}
+	return output.AnnotatedHeader(cfg.Format, outputColumns(cfg, cl))
+}
+
+// nameOutput formats a result. If cleaning is on, the output also has the
+// original input string. With the Within taxon the output shows if the
+// best result is within the taxon, or was promoted because of it. If
+// there are decisions of curators, names they changed are marked. In
+// resolve mode the output has accepted names
+// instead of matches.
+func nameOutput(
+	gnv gnverifier.GNverifier,
+	cl clean.Cleaner,
+	input string,
+	r vlib.Name,
+) string {
+	cfg := gnv.Config()
+	cols := outputColumns(cfg, cl)
+	res := gnv.Annotate([]string{input}, []vlib.Name{r})[0]
+	if !cols.Input {
+		res.Input = ""
+	}
+
+	if cfg.Resolve {
+		rsl := resolve.New(r, cfg.DataSources)
+		rsl.Input = res.Input
+		rsl.Decision = res.Decision
+		return output.ResolutionOutput(rsl, cfg.Format, cols)
+	}
+	if cols == (output.Columns{}) {
+		return output.NameOutput(r, cfg.Format)
+	}
+	return output.AnnotatedNameOutput(res, cfg.Format, cols)
+}
+
+// warnOutside reports names with the best match outside of the Within
+// taxon.
+func warnOutside(cfg config.Config, r vlib.Name) {
+	if cfg.Within == "" || within.Check(r, cfg.Within) != within.Outside {
+		return
+	}
+	slog.Warn("Best match is outside of the taxon",
+		"name", r.Name,
+		"within", cfg.Within,
+		"match", r.BestResult.MatchedName,
+		"classification", r.BestResult.ClassificationPath,
+	)
+}
+
+func logProgress(total int, timeStart time.Time) {
+	speed := int64(float64(total) / time.Since(timeStart).Seconds())
+	slog.Info("Verified.",
+		"names/sec", humanize.Comma(speed),
+		"names", humanize.Comma(int64(total)),
+	)
+}
+
+// inputQueue keeps input strings in the order they are sent for
+// verification.
+type inputQueue struct {
+	sync.Mutex
+	names []string
+}
+
+func (q *inputQueue) push(name string) {
+	q.Lock()
+	defer q.Unlock()
+	q.names = append(q.names, name)
+}
+
+func (q *inputQueue) pop() string {
+	q.Lock()
+	defer q.Unlock()
+	if len(q.names) == 0 {
+		return ""
+	}
+	res := q.names[0]
+	q.names = q.names[1:]
+	return res
 }
 
 func verifyString(gnv gnverifier.GNverifier, name string) {
-	res, err := gnv.VerifyOne(name)
+	cl := clean.New(gnv.Config().Cleaning...)
+	res, err := gnv.VerifyOne(context.Background(), name)
 	if err != nil {
 		slog.Error("Cannot verify name", "error", err, "name", name)
 	}
 
-	f := gnv.Config().Format
-	if f == gnfmt.CSV || f == gnfmt.TSV {
-		fmt.Println(output.CSVHeader(f))
+	cfg := gnv.Config()
+	warnOutside(cfg, res)
+	if cfg.Format == gnfmt.CSV || cfg.Format == gnfmt.TSV {
+		fmt.Println(outputHeader(cfg, cl))
 	}
-	fmt.Println(output.NameOutput(res, f))
+	fmt.Println(nameOutput(gnv, cl, name, res))
 }
 
 func searchQuery(gnv gnverifier.GNverifier, s string) {
-	gnq := gnquery.New()
-	inp := gnq.Parse(s)
-	if ds := gnv.Config().DataSources; len(ds) > 0 {
-		inp.DataSources = ds
-	}
-	if all := gnv.Config().WithAllMatches; all {
-		inp.WithAllMatches = all
-	}
+	inp := searchInput(gnv, s)
 	res, err := gnv.Search(context.Background(), inp)
 	if err != nil {
 		slog.Error("Cannot run search query", "error", err, "input", inp)
@@ -342,6 +633,20 @@ func searchQuery(gnv gnverifier.GNverifier, s string) {
 	}
 }
 
+// searchInput parses a search query. Data-sources and all matches
+// settings of the configuration override the ones of the query.
+func searchInput(gnv gnverifier.GNverifier, s string) search.Input {
+	gnq := gnquery.New()
+	inp := gnq.Parse(s)
+	if ds := gnv.Config().DataSources; len(ds) > 0 {
+		inp.DataSources = ds
+	}
+	if all := gnv.Config().WithAllMatches; all {
+		inp.WithAllMatches = all
+	}
+	return inp
+}
+
 // touchConfigFile checks if config file exists, and if not, it gets created.
 func touchConfigFile(configPath string) {
 	fileExists, _ := gnsys.FileExists(configPath)
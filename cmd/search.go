@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnquery/ent/search"
+	"github.com/gnames/gnsys"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/io/verifrest"
+	"github.com/spf13/cobra"
+)
+
+// searchCmd runs advanced search queries.
+var searchCmd = &cobra.Command{
+	Use:   "search [query|file]",
+	Short: "Runs advanced search queries from a file.",
+	Long: `Runs advanced search queries. Takes a query, a file with one query
+per line, or reads queries from standard input. Queries run in parallel,
+and every result is tagged with the query that found it.
+
+  examples:
+    gnverifier search "g:M. sp:galloprovincialis au:Oliv."
+    gnverifier search queries.txt -j 8 > results.csv
+    cat queries.txt | gnverifier search -s "1,11" -f compact`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := []funcFlag{
+			formatFlag, jobsFlag, allMatchesFlag, verifierUrlFlag,
+			sourcesFlag, quietFlag,
+		}
+		for _, f := range flags {
+			f(cmd)
+		}
+
+		cfg := config.New(opts...)
+		vfr := verifrest.New(cfg.VerifierURL)
+		gnv := gnverifier.New(cfg, vfr)
+
+		if len(args) == 0 {
+			if !checkStdin() {
+				_ = cmd.Help()
+				return
+			}
+			searchFile(gnv, os.Stdin)
+			return
+		}
+
+		if exists, _ := gnsys.FileExists(args[0]); exists {
+			f, err := os.Open(args[0])
+			if err != nil {
+				slog.Error("Cannot open file", "error", err, "file", args[0])
+				os.Exit(1)
+			}
+			defer f.Close()
+			searchFile(gnv, f)
+			return
+		}
+
+		printQueries(gnv, searchQueries(gnv, args), true)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	fs := searchCmd.Flags()
+	fs.StringP("format", "f", "", `Format of the output: "compact", "pretty", "csv", "tsv".`)
+	fs.StringP("sources", "s", "",
+		`IDs or names of data-sources to search in, overrides "ds:" of queries.`)
+	fs.BoolP("all_matches", "M", false, "return all matched results per source.")
+	fs.IntP("jobs", "j", 4, "Number of queries running in parallel.")
+	fs.StringP("verifier_url", "v", "", "URL for verification service.")
+	fs.BoolP("quiet", "q", false, "do not show progress")
+}
+
+// searchFile runs queries from a reader, one per line.
+func searchFile(gnv gnverifier.GNverifier, r io.Reader) {
+	var count int
+	readBatches(r, gnv.Config().Batch, func(qs []string, first bool) {
+		printQueries(gnv, searchQueries(gnv, qs), first)
+		count += len(qs)
+		slog.Info("Searched.", "queries", count)
+	})
+}
+
+// searchQueries runs queries using Jobs parallel workers. The results
+// keep the order of queries.
+func searchQueries(
+	gnv gnverifier.GNverifier,
+	queries []string,
+) []output.QueryResult {
+	return parallelMap(gnv.Config().Jobs, queries, func(q string) output.QueryResult {
+		return runQuery(gnv, q)
+	})
+}
+
+// runQuery runs one search query. Problems with the query or the search
+// are reported in the Error field of the result.
+func runQuery(gnv gnverifier.GNverifier, q string) output.QueryResult {
+	res := output.QueryResult{Query: q}
+	if !search.IsQuery(q) {
+		res.Error = "not a search query"
+		return res
+	}
+
+	inp := searchInput(gnv, q)
+	if len(inp.Warnings) > 0 {
+		slog.Warn("Problems with search query",
+			"query", q, "warnings", strings.Join(inp.Warnings, "; "))
+	}
+	names, err := gnv.Search(context.Background(), inp)
+	if err != nil {
+		res.Error = fmt.Sprintf("cannot run search: %s", err)
+	}
+	res.Names = names
+	return res
+}
+
+func printQueries(
+	gnv gnverifier.GNverifier,
+	res []output.QueryResult,
+	header bool,
+) {
+	f := gnv.Config().Format
+	if header && (f == gnfmt.CSV || f == gnfmt.TSV) {
+		fmt.Println(output.QueryHeader(f))
+	}
+	for _, v := range res {
+		if v.Error != "" {
+			slog.Error("Error during search", "query", v.Query, "error", v.Error)
+		}
+		fmt.Println(output.QueryOutput(v, f))
+	}
+}
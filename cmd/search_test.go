@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnquery/ent/search"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	vtest "github.com/gnames/gnverifier/pkg/ent/verifier/verifiertesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchQueries(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.SearchCalls(func(
+		_ context.Context,
+		inp search.Input,
+	) (search.Output, error) {
+		if inp.Genus == "Fail" {
+			return search.Output{}, errors.New("timeout")
+		}
+		name := vlib.Name{Name: inp.Genus + " " + inp.Species}
+		return search.Output{Names: []vlib.Name{name}}, nil
+	})
+	cfg := config.New(config.OptJobs(2), config.OptDataSources([]int{1}))
+	gnv := gnverifier.New(cfg, vfr)
+
+	qs := []string{
+		"g:Bubo sp:bubo",
+		"Bubo bubo",
+		"g:Fail sp:bubo",
+		"g:Aus sp:bus ds:11",
+	}
+	res := searchQueries(gnv, qs)
+	require.Len(t, res, len(qs))
+	for i := range qs {
+		assert.Equal(t, qs[i], res[i].Query)
+	}
+
+	require.Len(t, res[0].Names, 1)
+	assert.Equal(t, "Bubo bubo", res[0].Names[0].Name)
+	assert.Empty(t, res[0].Error)
+	assert.Equal(t, "not a search query", res[1].Error)
+	assert.Contains(t, res[2].Error, "timeout")
+	assert.Equal(t, "Aus bus", res[3].Names[0].Name)
+
+	assert.Equal(t, 3, vfr.SearchCallCount())
+	for i := range vfr.SearchCallCount() {
+		_, inp := vfr.SearchArgsForCall(i)
+		assert.Equal(t, []int{1}, inp.DataSources)
+	}
+}
@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnquery/ent/search"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/io/verifrest"
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+)
+
+const shellHelp = `Type a name-string to verify it, or an advanced search query
+(e.g. "g:M. sp:galloprovincialis") to search.
+
+Commands:
+  :sources                   list data-sources
+  :sources 1,11|col          return results from given data-sources
+  :sources off               stop returning results from selected data-sources
+  :fuzzy relaxed [on|off]    toggle relaxed fuzzy matching rules
+  :fuzzy uninomial [on|off]  toggle fuzzy matching of uninomials
+  :fuzzy off                 default fuzzy matching
+  :all on|off                return all matches per data-source
+  :format summary            short summary of results (default)
+  :format pretty             output format: "pretty", "compact", "csv", "tsv"
+  :details <n>               show all data of the n-th result
+  :config                    show current settings
+  :help                      show this help
+  :quit                      exit (or Ctrl-D)`
+
+// shellCmd runs an interactive verification session.
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Starts an interactive session for verification of names.",
+	Long: `Starts an interactive session where every line is verified, or
+searched if it is an advanced search query. Settings can be changed on the
+fly with commands that start with ':'. Type ':help' to see them.
+
+  examples:
+    gnverifier shell
+    gnverifier shell -s "1,11"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		flags := []funcFlag{
+			allMatchesFlag, verifierUrlFlag, sourcesFlag,
+			fuzzyRelaxedFlag, fuzzyUninomialFlag,
+		}
+		for _, f := range flags {
+			f(cmd)
+		}
+		// progress messages would interfere with the prompt.
+		slog.SetLogLoggerLevel(slog.LevelWarn)
+
+		cfg := config.New(opts...)
+		vfr := verifrest.New(cfg.VerifierURL)
+		gnv := gnverifier.New(cfg, vfr)
+		runShell(newShell(gnv, os.Stdout))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+	fs := shellCmd.Flags()
+	fs.StringP("sources", "s", "",
+		`IDs or names of data-sources to return results from (ex "1,11", "col").`)
+	fs.BoolP("all_matches", "M", false, "return all matched results per source.")
+	fs.BoolP("fuzzy_relaxed", "R", false, "relaxes fuzzy matching rules.")
+	fs.BoolP("fuzzy_uninomial", "U", false, "allows fuzzy matching for uninomial names.")
+	fs.StringP("verifier_url", "v", "", "URL for verification service.")
+}
+
+// shell keeps the state of an interactive session.
+type shell struct {
+	gnv gnverifier.GNverifier
+	out io.Writer
+
+	// summary is true if results are shown as short summaries instead of
+	// the configured format.
+	summary bool
+
+	// last keeps results of the last verification or search for the
+	// :details command.
+	last []vlib.Name
+}
+
+func newShell(gnv gnverifier.GNverifier, out io.Writer) *shell {
+	return &shell{gnv: gnv, out: out, summary: true}
+}
+
+// runShell reads lines with history and line editing until the user
+// quits.
+func runShell(sh *shell) {
+	ln := liner.NewLiner()
+	defer ln.Close()
+	ln.SetCtrlCAborts(true)
+
+	histPath := shellHistoryPath()
+	if f, err := os.Open(histPath); err == nil {
+		_, _ = ln.ReadHistory(f)
+		f.Close()
+	}
+
+	fmt.Fprintf(sh.out, "GNverifier %s. Type ':help' for commands.\n",
+		gnverifier.Version)
+	for {
+		line, err := ln.Prompt("gnverifier> ")
+		if errors.Is(err, liner.ErrPromptAborted) {
+			continue
+		}
+		if err != nil {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		ln.AppendHistory(line)
+		if !sh.exec(line) {
+			break
+		}
+	}
+
+	if histPath == "" {
+		return
+	}
+	_ = os.MkdirAll(filepath.Dir(histPath), 0o755)
+	if f, err := os.Create(histPath); err == nil {
+		_, _ = ln.WriteHistory(f)
+		f.Close()
+	}
+}
+
+func shellHistoryPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gnverifier", "shell_history")
+}
+
+// exec runs one line of input. It returns false if the session should
+// end.
+func (sh *shell) exec(line string) bool {
+	if !strings.HasPrefix(line, ":") {
+		sh.verify(line)
+		return true
+	}
+
+	cmd, arg, _ := strings.Cut(line[1:], " ")
+	arg = strings.TrimSpace(arg)
+	var err error
+	switch cmd {
+	case "q", "quit", "exit":
+		return false
+	case "h", "help":
+		fmt.Fprintln(sh.out, shellHelp)
+	case "sources":
+		err = sh.sources(arg)
+	case "fuzzy":
+		err = sh.fuzzy(arg)
+	case "all":
+		err = sh.allMatches(arg)
+	case "format":
+		err = sh.format(arg)
+	case "details":
+		err = sh.details(arg)
+	case "config":
+		sh.showConfig()
+	default:
+		err = fmt.Errorf("unknown command ':%s', type ':help' for commands", cmd)
+	}
+	if err != nil {
+		fmt.Fprintf(sh.out, "Error: %s\n", err)
+	}
+	return true
+}
+
+// verify verifies a name-string, or runs a search if the line is an
+// advanced search query.
+func (sh *shell) verify(line string) {
+	var err error
+	if search.IsQuery(line) {
+		sh.last, err = sh.gnv.Search(context.Background(), searchInput(sh.gnv, line))
+	} else {
+		var name vlib.Name
+		name, err = sh.gnv.VerifyOne(context.Background(), line)
+		sh.last = []vlib.Name{name}
+	}
+	if err != nil {
+		fmt.Fprintf(sh.out, "Error: %s\n", err)
+		return
+	}
+	if len(sh.last) == 0 {
+		fmt.Fprintln(sh.out, "Nothing found.")
+		return
+	}
+
+	if !sh.summary {
+		f := sh.gnv.Config().Format
+		if f == gnfmt.CSV || f == gnfmt.TSV {
+			fmt.Fprintln(sh.out, output.CSVHeader(f))
+		}
+		for _, v := range sh.last {
+			fmt.Fprintln(sh.out, output.NameOutput(v, f))
+		}
+		return
+	}
+	for i, v := range sh.last {
+		fmt.Fprintf(sh.out, "[%d] %s\n", i+1, nameSummary(v))
+	}
+}
+
+// nameSummary describes the best match of a name in one line, followed
+// by one line per result from selected data-sources.
+func nameSummary(n vlib.Name) string {
+	if n.Error != "" {
+		return fmt.Sprintf("%s: error: %s", n.Name, n.Error)
+	}
+	if n.BestResult == nil && len(n.Results) == 0 {
+		return fmt.Sprintf("%s: %s", n.Name, vlib.NoMatch)
+	}
+
+	res := []string{n.Name + ":"}
+	if n.BestResult != nil {
+		res = append(res, "    "+resultSummary(n.BestResult))
+	}
+	for _, v := range n.Results {
+		res = append(res, "  - "+resultSummary(v))
+	}
+	return strings.Join(res, "\n")
+}
+
+func resultSummary(r *vlib.ResultData) string {
+	res := fmt.Sprintf("%s %s (%s, %d)",
+		r.MatchType, r.MatchedName, r.DataSourceTitleShort, r.DataSourceID)
+	if r.CurrentName != "" && r.CurrentName != r.MatchedName {
+		res += fmt.Sprintf(" -> %s", r.CurrentName)
+	}
+	return res
+}
+
+// sources lists data-sources without arguments, otherwise it sets
+// data-sources for verification.
+func (sh *shell) sources(arg string) error {
+	if arg == "" {
+		dss, err := sh.gnv.DataSources(context.Background())
+		if err != nil {
+			return err
+		}
+		writeSourcesCache(sourcesCachePath(), dss)
+		fmt.Fprint(sh.out, output.DataSourcesOutput(dss, gnfmt.FormatNone))
+		return nil
+	}
+
+	var ids []int
+	if arg != "off" {
+		var err error
+		r := newSourceResolver(sh.gnv.Config().VerifierURL)
+		if ids, err = parseDataSources(arg, r); err != nil {
+			return err
+		}
+	}
+	sh.gnv = sh.gnv.ChangeConfig(config.OptDataSources(ids))
+	sh.showConfig()
+	return nil
+}
+
+// fuzzy changes relaxed and uninomial fuzzy matching independently. An
+// option without "on" or "off" is toggled.
+func (sh *shell) fuzzy(arg string) error {
+	cfg := sh.gnv.Config()
+	opt, state, _ := strings.Cut(arg, " ")
+	state = strings.TrimSpace(state)
+	toggle := func(cur bool) (bool, error) {
+		switch state {
+		case "":
+			return !cur, nil
+		case "on", "off":
+			return state == "on", nil
+		}
+		return cur, fmt.Errorf("unknown state '%s', use 'on' or 'off'", state)
+	}
+
+	var err error
+	relaxed, uninomial := cfg.WithRelaxedFuzzyMatch, cfg.WithUninomialFuzzyMatch
+	switch opt {
+	case "relaxed":
+		relaxed, err = toggle(relaxed)
+	case "uninomial":
+		uninomial, err = toggle(uninomial)
+	case "off":
+		relaxed, uninomial = false, false
+	default:
+		err = errors.New(
+			"use ':fuzzy relaxed [on|off]', ':fuzzy uninomial [on|off]' or ':fuzzy off'",
+		)
+	}
+	if err != nil {
+		return err
+	}
+	sh.gnv = sh.gnv.ChangeConfig(
+		config.OptWithRelaxedFuzzyMatch(relaxed),
+		config.OptWithUninomialFuzzyMatch(uninomial),
+	)
+	sh.showConfig()
+	return nil
+}
+
+func (sh *shell) allMatches(arg string) error {
+	if arg != "on" && arg != "off" {
+		return errors.New("use ':all on' or ':all off'")
+	}
+	sh.gnv = sh.gnv.ChangeConfig(config.OptWithAllMatches(arg == "on"))
+	sh.showConfig()
+	return nil
+}
+
+func (sh *shell) format(arg string) error {
+	if arg == "summary" {
+		sh.summary = true
+		return nil
+	}
+	f, _ := gnfmt.NewFormat(arg)
+	if f == gnfmt.FormatNone {
+		return fmt.Errorf(
+			"unknown format '%s', use summary, pretty, compact, csv or tsv", arg,
+		)
+	}
+	sh.summary = false
+	sh.gnv = sh.gnv.ChangeConfig(config.OptFormat(f))
+	return nil
+}
+
+// details shows all data of the n-th name from the last results.
+func (sh *shell) details(arg string) error {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(sh.last) {
+		return fmt.Errorf("use ':details <n>', where n is from 1 to %d", len(sh.last))
+	}
+	fmt.Fprintln(sh.out, output.NameOutput(sh.last[n-1], gnfmt.PrettyJSON))
+	return nil
+}
+
+func (sh *shell) showConfig() {
+	cfg := sh.gnv.Config()
+	format := "summary"
+	if !sh.summary {
+		format = cfg.Format.String()
+	}
+	var fs []string
+	if cfg.WithRelaxedFuzzyMatch {
+		fs = append(fs, "relaxed")
+	}
+	if cfg.WithUninomialFuzzyMatch {
+		fs = append(fs, "uninomial")
+	}
+	fuzzy := "default"
+	if len(fs) > 0 {
+		fuzzy = strings.Join(fs, "+")
+	}
+	sources := "off"
+	if len(cfg.DataSources) > 0 {
+		ss := make([]string, len(cfg.DataSources))
+		for i, v := range cfg.DataSources {
+			ss[i] = strconv.Itoa(v)
+		}
+		sources = strings.Join(ss, ",")
+	}
+	fmt.Fprintf(sh.out, "sources: %s, fuzzy: %s, all matches: %t, format: %s\n",
+		sources, fuzzy, cfg.WithAllMatches, format)
+}
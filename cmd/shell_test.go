@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnquery/ent/search"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	vtest "github.com/gnames/gnverifier/pkg/ent/verifier/verifiertesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(_ context.Context, inp vlib.Input) (vlib.Output, error) {
+		name := vlib.Name{
+			Name:      inp.NameStrings[0],
+			MatchType: vlib.Exact,
+			BestResult: &vlib.ResultData{
+				MatchType:            vlib.Exact,
+				MatchedName:          inp.NameStrings[0],
+				CurrentName:          "Bubo bubo (Linnaeus, 1758)",
+				DataSourceID:         1,
+				DataSourceTitleShort: "Catalogue of Life",
+			},
+		}
+		return vlib.Output{Names: []vlib.Name{name}}, nil
+	})
+	vfr.SearchReturns(search.Output{Names: []vlib.Name{
+		{Name: "Bubo bubo"}, {Name: "Bubo bengalensis"},
+	}}, nil)
+	vfr.DataSourcesReturns([]vlib.DataSource{
+		{ID: 1, Title: "Catalogue of Life", TitleShort: "Catalogue of Life"},
+	}, nil)
+
+	gnv := gnverifier.New(config.New(), vfr)
+	var out bytes.Buffer
+	sh := newShell(gnv, &out)
+
+	assert.True(t, sh.exec("Strix bubo"))
+	assert.Contains(t, out.String(), "[1] Strix bubo:")
+	assert.Contains(t, out.String(), "Exact Strix bubo (Catalogue of Life, 1)")
+	assert.Contains(t, out.String(), "-> Bubo bubo (Linnaeus, 1758)")
+
+	out.Reset()
+	sh.exec("g:Bubo sp:b")
+	assert.Contains(t, out.String(), "[2] Bubo bengalensis: NoMatch")
+	assert.Equal(t, 1, vfr.SearchCallCount())
+
+	out.Reset()
+	sh.exec(":details 2")
+	assert.Contains(t, out.String(), `"name": "Bubo bengalensis"`)
+	out.Reset()
+	sh.exec(":details 3")
+	assert.Contains(t, out.String(), "Error:")
+
+	sh.exec(":sources 1,11")
+	assert.Equal(t, []int{1, 11}, sh.gnv.Config().DataSources)
+	sh.exec(":sources off")
+	assert.Empty(t, sh.gnv.Config().DataSources)
+
+	out.Reset()
+	sh.exec(":sources")
+	assert.Contains(t, out.String(), "Catalogue of Life")
+
+	sh.exec(":fuzzy relaxed")
+	assert.True(t, sh.gnv.Config().WithRelaxedFuzzyMatch)
+	out.Reset()
+	sh.exec(":fuzzy uninomial")
+	assert.True(t, sh.gnv.Config().WithRelaxedFuzzyMatch)
+	assert.True(t, sh.gnv.Config().WithUninomialFuzzyMatch)
+	assert.Contains(t, out.String(), "fuzzy: relaxed+uninomial")
+	sh.exec(":fuzzy relaxed")
+	assert.False(t, sh.gnv.Config().WithRelaxedFuzzyMatch)
+	assert.True(t, sh.gnv.Config().WithUninomialFuzzyMatch)
+	sh.exec(":fuzzy relaxed on")
+	sh.exec(":fuzzy relaxed on")
+	assert.True(t, sh.gnv.Config().WithRelaxedFuzzyMatch)
+	out.Reset()
+	sh.exec(":fuzzy relaxed maybe")
+	assert.Contains(t, out.String(), "Error:")
+	sh.exec(":fuzzy off")
+	assert.False(t, sh.gnv.Config().WithRelaxedFuzzyMatch)
+	assert.False(t, sh.gnv.Config().WithUninomialFuzzyMatch)
+
+	sh.exec(":all on")
+	assert.True(t, sh.gnv.Config().WithAllMatches)
+
+	sh.exec(":format pretty")
+	assert.False(t, sh.summary)
+	assert.Equal(t, gnfmt.PrettyJSON, sh.gnv.Config().Format)
+	out.Reset()
+	sh.exec("Strix bubo")
+	assert.Contains(t, out.String(), `"bestResult": {`)
+	_, inp := vfr.VerifyArgsForCall(vfr.VerifyCallCount() - 1)
+	assert.True(t, inp.WithAllMatches)
+
+	out.Reset()
+	sh.exec(":format bad")
+	assert.Contains(t, out.String(), "Error: unknown format")
+	sh.exec(":format summary")
+	assert.True(t, sh.summary)
+
+	out.Reset()
+	sh.exec(":nope")
+	assert.Contains(t, out.String(), "unknown command")
+
+	require.False(t, sh.exec(":quit"))
+}
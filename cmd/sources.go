@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/io/verifrest"
+	"github.com/spf13/cobra"
+)
+
+// sourcesCmd lists data-sources available for verification.
+var sourcesCmd = &cobra.Command{
+	Use:   "sources [id]",
+	Short: "Lists data-sources available for verification.",
+	Long: `Lists data-sources that are used for verification. Use their IDs
+with the --sources flag. If an ID is given, shows full metadata of the
+data-source.
+
+  examples:
+    gnverifier sources
+    gnverifier sources --title "catalogue"
+    gnverifier sources --curation curated -f pretty
+    gnverifier sources 11`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		verifierUrlFlag(cmd)
+		frmt := sourcesFormat(cmd)
+
+		cfg := config.New(opts...)
+		vfr := verifrest.New(cfg.VerifierURL)
+		gnv := gnverifier.New(cfg, vfr)
+
+		if len(args) == 1 {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				slog.Error("Data-source ID must be a number", "id", args[0])
+				os.Exit(1)
+			}
+			ds, err := gnv.DataSource(context.Background(), id)
+			if err != nil {
+				slog.Error("Cannot get data-source", "id", id, "error", err)
+				os.Exit(1)
+			}
+			fmt.Println(output.DataSourceOutput(ds, frmt))
+			return
+		}
+
+		dss, err := gnv.DataSources(context.Background())
+		if err != nil {
+			slog.Error("Cannot get data-sources", "error", err)
+			os.Exit(1)
+		}
+		writeSourcesCache(sourcesCachePath(), dss)
+		title, _ := cmd.Flags().GetString("title")
+		curation, _ := cmd.Flags().GetString("curation")
+		dss, err = filterSources(dss, title, curation)
+		if err != nil {
+			slog.Error("Cannot filter data-sources", "error", err)
+			os.Exit(1)
+		}
+		fmt.Println(output.DataSourcesOutput(dss, frmt))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sourcesCmd)
+	sourcesCmd.Flags().StringP("format", "f", "",
+		`Format of the output: "table" (DEFAULT), "compact", "pretty", "csv", "tsv".`)
+	sourcesCmd.Flags().StringP("title", "t", "",
+		"show only data-sources with titles containing the string.")
+	sourcesCmd.Flags().String("curation", "",
+		`show only data-sources with given curation levels
+  (e.g. "curated", "curated,auto", "none").`)
+	sourcesCmd.Flags().StringP("verifier_url", "v", "",
+		"URL for verification service.")
+}
+
+// sourcesFormat returns the output format for the sources command.
+// FormatNone means a table.
+func sourcesFormat(cmd *cobra.Command) gnfmt.Format {
+	s, _ := cmd.Flags().GetString("format")
+	if s == "" || s == "table" {
+		return gnfmt.FormatNone
+	}
+	res, _ := gnfmt.NewFormat(s)
+	if res == gnfmt.FormatNone {
+		slog.Warn("Unknown format, showing a table", "input", s)
+	}
+	return res
+}
+
+// filterSources keeps data-sources that contain title in their full or
+// short titles (case-insensitive), and have one of the given curation
+// levels. Empty filters are ignored.
+func filterSources(
+	dss []vlib.DataSource,
+	title, curation string,
+) ([]vlib.DataSource, error) {
+	levels, err := parseCurationLevels(curation)
+	if err != nil {
+		return nil, err
+	}
+	title = strings.ToLower(strings.TrimSpace(title))
+
+	var res []vlib.DataSource
+	for _, ds := range dss {
+		if title != "" &&
+			!strings.Contains(strings.ToLower(ds.Title), title) &&
+			!strings.Contains(strings.ToLower(ds.TitleShort), title) {
+			continue
+		}
+		if len(levels) > 0 && !slices.Contains(levels, ds.Curation) {
+			continue
+		}
+		res = append(res, ds)
+	}
+	return res, nil
+}
+
+// parseCurationLevels converts a comma-separated list of curation levels
+// into vlib.CurationLevel values.
+func parseCurationLevels(s string) ([]vlib.CurationLevel, error) {
+	var res []vlib.CurationLevel
+	for _, v := range parseList(s) {
+		switch strings.ToLower(strings.ReplaceAll(v, "_", "")) {
+		case "curated":
+			res = append(res, vlib.Curated)
+		case "auto", "autocurated":
+			res = append(res, vlib.AutoCurated)
+		case "none", "notcurated":
+			res = append(res, vlib.NotCurated)
+		default:
+			return nil, fmt.Errorf(
+				"unknown curation level '%s', use 'curated', 'auto' or 'none'", v,
+			)
+		}
+	}
+	return res, nil
+}
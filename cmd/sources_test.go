@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterSources(t *testing.T) {
+	dss := []vlib.DataSource{
+		{ID: 1, Title: "Catalogue of Life", TitleShort: "CoL", Curation: vlib.Curated},
+		{ID: 11, Title: "Global Biodiversity Information Facility Backbone Taxonomy",
+			TitleShort: "GBIF Backbone Taxonomy", Curation: vlib.AutoCurated},
+		{ID: 170, Title: "Arctos", TitleShort: "Arctos", Curation: vlib.NotCurated},
+	}
+
+	tests := []struct {
+		name     string
+		title    string
+		curation string
+		ids      []int
+		hasErr   bool
+	}{
+		{"no filters", "", "", []int{1, 11, 170}, false},
+		{"title", "catalogue", "", []int{1}, false},
+		{"short title", "gbif", "", []int{11}, false},
+		{"curation", "", "curated", []int{1}, false},
+		{"several levels", "", "Curated, auto_curated", []int{1, 11}, false},
+		{"not curated", "", "none", []int{170}, false},
+		{"both", "o", "auto", []int{11}, false},
+		{"nothing found", "zzz", "", nil, false},
+		{"bad level", "", "best", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := filterSources(dss, tt.title, tt.curation)
+			if tt.hasErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var ids []int
+			for _, v := range res {
+				ids = append(ids, v.ID)
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
+
+func TestSourcesCommand(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"sources"})
+	require.NoError(t, err)
+	assert.Equal(t, "sources", cmd.Name())
+	for _, v := range []string{"format", "title", "curation", "verifier_url"} {
+		assert.NotNil(t, cmd.Flags().Lookup(v), v)
+	}
+
+	cmd, args, err := rootCmd.Find([]string{"Bubo bubo"})
+	require.NoError(t, err)
+	assert.Equal(t, rootCmd, cmd)
+	assert.Equal(t, []string{"Bubo bubo"}, args)
+}
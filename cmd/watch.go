@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gnames/gnfmt"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/io/verifrest"
+	"github.com/spf13/cobra"
+)
+
+// resultMark is a part of file names of results. Files with it are not
+// verified.
+const resultMark = ".verified"
+
+// watchCmd verifies files dropped into a folder.
+var watchCmd = &cobra.Command{
+	Use:   "watch dir",
+	Short: "Verifies files that appear in a folder.",
+	Long: `Watches a folder and verifies every new file in it, one name per
+line. Results are written next to the input file (or into --output_dir)
+as '<file>.verified.<format>'. Processed files are moved to the 'archive'
+subfolder, files that could not be processed are moved to the 'failed'
+subfolder. If only some names failed, results of other names are kept.
+Files that are already in the folder are processed on start. Stop
+watching with Ctrl-C.
+
+  examples:
+    gnverifier watch /data/drop
+    gnverifier watch /data/drop -o /data/results -s "1,11" -f tsv`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := []funcFlag{
+			capitalizeFlag, spGroupFlag, fuzzyRelaxedFlag, fuzzyUninomialFlag,
+			formatFlag, jobsFlag, allMatchesFlag, verifierUrlFlag, sourcesFlag,
+			vernacularsFlag, quietFlag, dedupFlag, cleanFlag,
+			resolveFlag, withinFlag, decisionsFlag,
+		}
+		for _, f := range flags {
+			f(cmd)
+		}
+
+		cfg := config.New(opts...)
+		vfr := verifrest.New(cfg.VerifierURL)
+		gnv := gnverifier.New(cfg, vfr)
+
+		outDir, _ := cmd.Flags().GetString("output_dir")
+		w, err := newWatcher(gnv, args[0], outDir)
+		if err != nil {
+			slog.Error("Cannot watch folder", "error", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(
+			context.Background(), syscall.SIGINT, syscall.SIGTERM,
+		)
+		defer stop()
+		if err = w.run(ctx); err != nil {
+			slog.Error("Watching stopped", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	fs := watchCmd.Flags()
+	fs.StringP("output_dir", "o", "", "folder for results (default the watched folder).")
+	fs.StringP("format", "f", "", `Format of the output: "compact", "pretty", "csv", "tsv".`)
+	fs.StringP("sources", "s", "",
+		`IDs or names of data-sources to return results from (ex "1,11", "col").`)
+	fs.BoolP("all_matches", "M", false, "return all matched results per source.")
+	fs.BoolP("capitalize", "c", false, "capitalizes first character")
+	fs.BoolP("species_group", "G", false, "searching for species names also searches their species groups.")
+	fs.BoolP("fuzzy_relaxed", "R", false, "relaxes fuzzy matching rules.")
+	fs.BoolP("fuzzy_uninomial", "U", false, "allows fuzzy matching for uninomial names.")
+	fs.StringP("vernaculars", "r", "", `sets languages for vernacular names search (e.g., "eng,deu").`)
+	fs.IntP("jobs", "j", 4, "Number of jobs running in parallel.")
+	fs.String("dedup", "", `verify repeated names once: "exact" or "normalized".`)
+	fs.String("clean", "", `cleans input names, "all" or a list of steps (e.g., "html,caps").`)
+	fs.Bool("resolve", false, "returns accepted names of input, one per data-source.")
+	fs.String("decisions", "", "file with decisions of curators (CSV, TSV or YAML).")
+	fs.String("within", "", `higher taxon where names are expected to belong (e.g., "Aves").`)
+	fs.StringP("verifier_url", "v", "", "URL for verification service.")
+	fs.BoolP("quiet", "q", false, "do not show progress")
+}
+
+// watcher verifies files that appear in a folder.
+type watcher struct {
+	gnv        gnverifier.GNverifier
+	dir        string
+	outDir     string
+	archiveDir string
+	failedDir  string
+
+	// settle is the time without changes of a file after which the file
+	// is considered completely written.
+	settle time.Duration
+}
+
+func newWatcher(
+	gnv gnverifier.GNverifier,
+	dir, outDir string,
+) (*watcher, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("'%s' is not a folder", dir)
+	}
+	if outDir == "" {
+		outDir = dir
+	}
+
+	res := &watcher{
+		gnv:        gnv,
+		dir:        dir,
+		outDir:     outDir,
+		archiveDir: filepath.Join(dir, "archive"),
+		failedDir:  filepath.Join(dir, "failed"),
+		settle:     2 * time.Second,
+	}
+	for _, v := range []string{res.outDir, res.archiveDir, res.failedDir} {
+		if err = os.MkdirAll(v, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// run processes files already present in the folder, and then new files
+// until the context is canceled.
+func (w *watcher) run(ctx context.Context) error {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fw.Close()
+	if err = fw.Add(w.dir); err != nil {
+		return err
+	}
+	slog.Info("Watching folder", "dir", w.dir, "output", w.outDir)
+
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+	for _, v := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		w.handle(ctx, filepath.Join(w.dir, v.Name()))
+	}
+
+	// pending keeps the time of the last change of files that are being
+	// written.
+	pending := make(map[string]time.Time)
+	tick := time.NewTicker(w.settle / 4)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped watching folder", "dir", w.dir)
+			return nil
+		case ev, ok := <-fw.Events:
+			if !ok {
+				return errors.New("file watcher closed")
+			}
+			if ev.Has(fsnotify.Create) || ev.Has(fsnotify.Write) {
+				pending[ev.Name] = time.Now()
+			}
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return errors.New("file watcher closed")
+			}
+			slog.Error("Problem with watching folder", "error", err)
+		case now := <-tick.C:
+			for path, t := range pending {
+				if now.Sub(t) >= w.settle {
+					delete(pending, path)
+					w.handle(ctx, path)
+				}
+			}
+		}
+	}
+}
+
+// handle verifies a file if it is an input file, and moves it to archive
+// or failed folder. If some names could not be verified, results of other
+// names are kept and the file is moved to the failed folder. If the
+// context is canceled, the file stays in the folder to be verified on the
+// next start. Problems are logged.
+func (w *watcher) handle(ctx context.Context, path string) {
+	if w.skip(path) {
+		return
+	}
+	slog.Info("Verifying file", "file", path)
+	start := time.Now()
+
+	moveTo := w.archiveDir
+	res, err := w.process(ctx, path)
+	switch {
+	case ctx.Err() != nil:
+		slog.Warn("Verification of file is interrupted", "file", path)
+		return
+	case err != nil && res != "":
+		slog.Error("Some names of file are not verified",
+			"file", path, "results", res, "error", err)
+		moveTo = w.failedDir
+	case err != nil:
+		slog.Error("Cannot verify file", "file", path, "error", err)
+		moveTo = w.failedDir
+	}
+	if err = moveFile(path, moveTo); err != nil {
+		slog.Error("Cannot move file", "file", path, "error", err)
+		return
+	}
+	if res != "" && moveTo == w.archiveDir {
+		slog.Info("File is verified",
+			"file", path, "results", res, "duration", time.Since(start))
+	}
+}
+
+// skip returns true for folders, hidden files and results.
+func (w *watcher) skip(path string) bool {
+	base := filepath.Base(path)
+	if strings.HasPrefix(base, ".") || strings.Contains(base, resultMark) {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err != nil || !info.Mode().IsRegular()
+}
+
+// process verifies names of a file and returns the path to results. If
+// some names could not be verified, the results are kept, they have the
+// error of such names, and the error is returned too.
+func (w *watcher) process(ctx context.Context, path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	res := filepath.Join(w.outDir, w.resultName(path))
+	tmp := res + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	bw := bufio.NewWriter(out)
+	verr := verifyFile(ctx, w.gnv, in, bw)
+	err = bw.Flush()
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err == nil {
+		err = os.Rename(tmp, res)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return res, verr
+}
+
+func (w *watcher) resultName(path string) string {
+	base := filepath.Base(path)
+	ext := "csv"
+	switch w.gnv.Config().Format {
+	case gnfmt.TSV:
+		ext = "tsv"
+	case gnfmt.CompactJSON:
+		ext = "jsonl"
+	case gnfmt.PrettyJSON:
+		ext = "json"
+	}
+	return base + resultMark + "." + ext
+}
+
+// moveFile moves a file into a folder. If the folder already has a file
+// with the same name, the time is added to the name.
+func moveFile(path, dir string) error {
+	dst := filepath.Join(dir, filepath.Base(path))
+	if _, err := os.Stat(dst); err == nil {
+		stamp := time.Now().Format("20060102-150405")
+		dst = filepath.Join(dir, stamp+"-"+filepath.Base(path))
+	}
+	return os.Rename(path, dst)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	vtest "github.com/gnames/gnverifier/pkg/ent/verifier/verifiertesting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func watchVerifier(opts ...config.Option) gnverifier.GNverifier {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(_ context.Context, inp vlib.Input) (vlib.Output, error) {
+		res := vlib.Output{Names: make([]vlib.Name, len(inp.NameStrings))}
+		for i, v := range inp.NameStrings {
+			res.Names[i] = vlib.Name{Name: v, MatchType: vlib.Exact}
+		}
+		return res, nil
+	})
+	return gnverifier.New(config.New(opts...), vfr)
+}
+
+func TestWatcherProcess(t *testing.T) {
+	dir := t.TempDir()
+	outDir := filepath.Join(dir, "out")
+	gnv := watchVerifier(config.OptFormat(gnfmt.TSV))
+	w, err := newWatcher(gnv, dir, outDir)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "names.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Bubo bubo\nPomatomus\n"), 0o644))
+	w.handle(context.Background(), path)
+
+	bs, err := os.ReadFile(filepath.Join(outDir, "names.txt.verified.tsv"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(bs)), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, string(bs), "Bubo bubo")
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "archive", "names.txt"))
+	assert.NoError(t, err)
+
+	// the same file name again does not overwrite the archived file.
+	require.NoError(t, os.WriteFile(path, []byte("Aves\n"), 0o644))
+	w.handle(context.Background(), path)
+	entries, err := os.ReadDir(filepath.Join(dir, "archive"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestWatcherPartial(t *testing.T) {
+	dir := t.TempDir()
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(_ context.Context, inp vlib.Input) (vlib.Output, error) {
+		if inp.NameStrings[0] == "Fail" {
+			return vlib.Output{}, gnverifier.ErrUpstream{Status: 500}
+		}
+		res := vlib.Output{Names: make([]vlib.Name, len(inp.NameStrings))}
+		for i, v := range inp.NameStrings {
+			res.Names[i] = vlib.Name{Name: v, MatchType: vlib.Exact}
+		}
+		return res, nil
+	})
+	gnv := gnverifier.New(config.New(config.OptBatch(1)), vfr)
+	w, err := newWatcher(gnv, dir, "")
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "names.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Bubo bubo\nFail\n"), 0o644))
+	w.handle(context.Background(), path)
+
+	// results of verified names are kept.
+	bs, err := os.ReadFile(filepath.Join(dir, "names.txt.verified.csv"))
+	require.NoError(t, err)
+	assert.Contains(t, string(bs), "Bubo bubo")
+	_, err = os.Stat(filepath.Join(dir, "failed", "names.txt"))
+	assert.NoError(t, err)
+
+	// an interrupted file stays in the folder.
+	require.NoError(t, os.WriteFile(path, []byte("Pomatomus\n"), 0o644))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.handle(ctx, path)
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "names.txt.verified.csv.tmp"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLineRanges(t *testing.T) {
+	var lr lineRanges
+	for _, v := range []int{3, 4, 5, 9, 11, 12} {
+		lr.add(v)
+	}
+	assert.Equal(t, "3-5, 9, 11-12", lr.String())
+}
+
+func TestWatcherSkip(t *testing.T) {
+	dir := t.TempDir()
+	w, err := newWatcher(watchVerifier(), dir, "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		file string
+		skip bool
+	}{
+		{"names.txt", false},
+		{".hidden", true},
+		{"names.txt.verified.csv", true},
+		{"names.txt.verified.csv.tmp", true},
+		{"archive", true},
+		{"missing.txt", true},
+	}
+	for _, v := range []string{"names.txt", ".hidden", "names.txt.verified.csv",
+		"names.txt.verified.csv.tmp"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, v), nil, 0o644))
+	}
+	for _, v := range tests {
+		assert.Equal(t, v.skip, w.skip(filepath.Join(dir, v.file)), v.file)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	dir := t.TempDir()
+	w, err := newWatcher(watchVerifier(), dir, "")
+	require.NoError(t, err)
+	w.settle = 50 * time.Millisecond
+
+	old := filepath.Join(dir, "old.txt")
+	require.NoError(t, os.WriteFile(old, []byte("Bubo bubo\n"), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.run(ctx) }()
+
+	// a file that was in the folder before start.
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, "old.txt.verified.csv"))
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"),
+		[]byte("Pomatomus\n"), 0o644))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, "archive", "new.txt"))
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond)
+
+	bs, err := os.ReadFile(filepath.Join(dir, "new.txt.verified.csv"))
+	require.NoError(t, err)
+	assert.Contains(t, string(bs), "Pomatomus")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -5,6 +5,7 @@ go 1.25.1
 require (
 	github.com/dnaeon/go-vcr v1.2.0
 	github.com/dustin/go-humanize v1.0.1
+	github.com/fsnotify/fsnotify v1.9.0
 	github.com/gnames/gnfmt v0.6.5
 	github.com/gnames/gnlib v0.64.0
 	github.com/gnames/gnquery v0.4.2
@@ -14,9 +15,12 @@ require (
 	github.com/labstack/gommon v0.4.2
 	github.com/lmittmann/tint v1.1.3
 	github.com/maxbrunsfeld/counterfeiter/v6 v6.12.1
+	github.com/peterh/liner v1.2.2
 	github.com/spf13/cobra v1.10.2
 	github.com/spf13/viper v1.21.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/text v0.35.0
+	golang.org/x/time v0.15.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
@@ -26,7 +30,6 @@ require (
 	github.com/clipperhouse/uax29/v2 v2.7.0 // indirect
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/fatih/color v1.19.0 // indirect
-	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/gnames/gnstats v0.2.1 // indirect
 	github.com/go-viper/mapstructure/v2 v2.5.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
@@ -57,8 +60,6 @@ require (
 	golang.org/x/sync v0.20.0 // indirect
 	golang.org/x/sys v0.42.0 // indirect
 	golang.org/x/telemetry v0.0.0-20260316223853-b6b0c46d1ccd // indirect
-	golang.org/x/text v0.35.0 // indirect
-	golang.org/x/time v0.15.0 // indirect
 	golang.org/x/tools v0.43.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -1,7 +1,13 @@
 package config
 
 import (
+	"strings"
+	"time"
+
 	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnverifier/pkg/ent/clean"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
+	"github.com/gnames/gnverifier/pkg/ent/observer"
 )
 
 // Config collects and stores external configuration data.
@@ -10,6 +16,12 @@ type Config struct {
 	// verification.
 	Batch int
 
+	// Cleaning are steps that clean input name-strings before
+	// verification and deduplication. The Name field of results has
+	// cleaned strings, results keep the order of the input, so callers
+	// can pair them with original strings.
+	Cleaning []clean.Step
+
 	// DataSources are IDs of DataSources that are important for
 	// user. Normally only one "the best" reusult returns. If user gives
 	// preferred sources, then matches from these sources are also
@@ -20,6 +32,14 @@ type Config struct {
 	// to use for finding vernacular names for results.
 	Vernaculars []string
 
+	// Decisions are decisions of curators that override results of
+	// verification of names.
+	Decisions curation.Decisions
+
+	// Dedup determines if repeated names of a run are verified only once.
+	// Results of such names are copied to every occurrence.
+	Dedup DedupMode
+
 	// Format determins the output. It can be either JSON or CSV.
 	Format gnfmt.Format
 
@@ -30,10 +50,86 @@ type Config struct {
 	// to GET.
 	NamesNumThreshold int
 
+	// Observer receives progress events of VerifyStream and VerifyIter.
+	// If it is nil, no events are sent.
+	Observer observer.Observer
+
+	// Resolve changes output of verification to accepted names of input,
+	// one per data-source (see GNverifier.Resolve).
+	Resolve bool
+
+	// VerificationTimeout is the maximum time given to VerifyBatch to get
+	// results for one batch of names. If it is zero, there is no timeout.
+	VerificationTimeout time.Duration
+
 	// VerifierURL URL for gnames verification service. It only needs to
 	// be changed if user sets local version of gnames.
 	VerifierURL string
 
+	// WebAddress is the IP address or host name of a network interface the
+	// web server binds to. If it is empty, the server listens on all
+	// interfaces.
+	WebAddress string
+
+	// WebAPIKeysFile is a path to a YAML file with API keys of clients. If it
+	// is set, the web server requires a valid key for every request and
+	// enforces quotas assigned to the keys.
+	WebAPIKeysFile string
+
+	// WebBasePath is a URL prefix under which the web application is
+	// mounted, for example "/tools/gnverifier". It is useful when the
+	// server runs behind a reverse proxy. It never has a trailing slash.
+	WebBasePath string
+
+	// WebCORSHeaders are request headers allowed in cross-origin requests.
+	// If empty, headers requested by a browser are allowed.
+	WebCORSHeaders []string
+
+	// WebCORSMethods are HTTP methods allowed in cross-origin requests.
+	// If empty, GET, HEAD and POST are allowed.
+	WebCORSMethods []string
+
+	// WebCORSOrigins are origins (for example "https://example.org") that
+	// are allowed to call the web server from a browser. The "*" value
+	// allows any origin. If empty, CORS support is switched off.
+	WebCORSOrigins []string
+
+	// WebIdleTimeout is the maximum time the web server keeps an idle
+	// keep-alive connection open.
+	WebIdleTimeout time.Duration
+
+	// WebPresets are named sets of verification settings that the web
+	// GUI offers to fill its form.
+	WebPresets []Preset
+
+	// WebPublicUI flag; when true, HTML pages of the web server are
+	// accessible without an API key even if WebAPIKeysFile is set.
+	WebPublicUI bool
+
+	// WebReadTimeout is the maximum time the web server spends on reading
+	// a request, including its body.
+	WebReadTimeout time.Duration
+
+	// WebShutdownTimeout is the time the web server gives in-flight
+	// requests to finish after receiving SIGINT or SIGTERM.
+	WebShutdownTimeout time.Duration
+
+	// WebTLSCert is a path to a PEM-encoded certificate. If it is given
+	// together with WebTLSKey, the web server uses HTTPS.
+	WebTLSCert string
+
+	// WebTLSKey is a path to a PEM-encoded private key for WebTLSCert.
+	WebTLSKey string
+
+	// WebTLSSelfSigned flag; when true and no certificate is given, the web
+	// server uses HTTPS with a self-signed certificate generated on start.
+	// It is meant for development only.
+	WebTLSSelfSigned bool
+
+	// WebWriteTimeout is the maximum time the web server spends on
+	// processing a request and writing its response.
+	WebWriteTimeout time.Duration
+
 	// WithAllMatches flag; if true, results include all matches per source,
 	// not only the best match.
 	WithAllMatches bool
@@ -61,11 +157,55 @@ type Config struct {
 	// restricted from fuzzy matching. Normally it creates too many false
 	// positives and is switched off.
 	WithUninomialFuzzyMatch bool
+
+	// Within is a higher taxon, for example "Aves", where names are
+	// expected to belong. Results from this taxon are preferred over
+	// results with classification outside of it.
+	Within string
+}
+
+// Preset is a named set of verification settings. The web GUI uses
+// presets to fill the verification form.
+type Preset struct {
+	// Name of the preset.
+	Name string
+
+	// DataSources are IDs of data-sources selected by the preset.
+	DataSources []int
+
+	// Vernaculars are languages of vernacular names.
+	Vernaculars []string
+
+	// WithAllMatches flag; returns all matches per data-source.
+	WithAllMatches bool
+
+	// WithSpeciesGroup flag; searches species groups.
+	WithSpeciesGroup bool
+
+	// WithRelaxedFuzzyMatch flag; relaxes fuzzy matching rules.
+	WithRelaxedFuzzyMatch bool
+
+	// WithUninomialFuzzyMatch flag; allows fuzzy matching of uninomials.
+	WithUninomialFuzzyMatch bool
 }
 
 // Option is a type of all options for Config.
 type Option func(cnf *Config)
 
+// OptBatch sets the number of names in one batch of verification.
+func OptBatch(i int) Option {
+	return func(cnf *Config) {
+		cnf.Batch = i
+	}
+}
+
+// OptCleaning sets steps of cleaning of input name-strings.
+func OptCleaning(steps []clean.Step) Option {
+	return func(cnf *Config) {
+		cnf.Cleaning = steps
+	}
+}
+
 // OptDataSources sets list of preferred sources.
 func OptDataSources(srs []int) Option {
 	return func(cnf *Config) {
@@ -80,6 +220,20 @@ func OptVernaculars(ss []string) Option {
 	}
 }
 
+// OptDecisions sets decisions of curators.
+func OptDecisions(ds curation.Decisions) Option {
+	return func(cnf *Config) {
+		cnf.Decisions = ds
+	}
+}
+
+// OptDedup sets deduplication of input names.
+func OptDedup(d DedupMode) Option {
+	return func(cnf *Config) {
+		cnf.Dedup = d
+	}
+}
+
 // OptFormat sets output format.
 func OptFormat(f gnfmt.Format) Option {
 	return func(cnf *Config) {
@@ -102,6 +256,20 @@ func OptNamesNumThreshold(i int) Option {
 	}
 }
 
+// OptObserver sets an observer of verification progress.
+func OptObserver(o observer.Observer) Option {
+	return func(cnf *Config) {
+		cnf.Observer = o
+	}
+}
+
+// OptResolve sets Resolve field.
+func OptResolve(b bool) Option {
+	return func(cnf *Config) {
+		cnf.Resolve = b
+	}
+}
+
 // OptVerifierURL sets URL of the verification resource.
 func OptVerifierURL(s string) Option {
 	return func(cnf *Config) {
@@ -109,6 +277,126 @@ func OptVerifierURL(s string) Option {
 	}
 }
 
+// OptVerificationTimeout sets the maximum duration of VerifyBatch.
+func OptVerificationTimeout(d time.Duration) Option {
+	return func(cnf *Config) {
+		cnf.VerificationTimeout = d
+	}
+}
+
+// OptWebAddress sets the address the web server binds to.
+func OptWebAddress(s string) Option {
+	return func(cnf *Config) {
+		cnf.WebAddress = s
+	}
+}
+
+// OptWebAPIKeysFile sets path to a file with API keys.
+func OptWebAPIKeysFile(s string) Option {
+	return func(cnf *Config) {
+		cnf.WebAPIKeysFile = s
+	}
+}
+
+// OptWebBasePath sets URL prefix of the web application. The prefix is
+// normalized to start with a slash and to have no trailing slash, so
+// "tools/gnverifier/" becomes "/tools/gnverifier", and "/" becomes an
+// empty string.
+func OptWebBasePath(s string) Option {
+	return func(cnf *Config) {
+		s = strings.Trim(strings.TrimSpace(s), "/")
+		if s != "" {
+			s = "/" + s
+		}
+		cnf.WebBasePath = s
+	}
+}
+
+// OptWebCORSHeaders sets request headers allowed for CORS requests.
+func OptWebCORSHeaders(ss []string) Option {
+	return func(cnf *Config) {
+		cnf.WebCORSHeaders = ss
+	}
+}
+
+// OptWebCORSMethods sets HTTP methods allowed for CORS requests.
+func OptWebCORSMethods(ss []string) Option {
+	return func(cnf *Config) {
+		cnf.WebCORSMethods = ss
+	}
+}
+
+// OptWebCORSOrigins sets origins allowed for CORS requests.
+func OptWebCORSOrigins(ss []string) Option {
+	return func(cnf *Config) {
+		cnf.WebCORSOrigins = ss
+	}
+}
+
+// OptWebIdleTimeout sets idle timeout of the web server.
+func OptWebIdleTimeout(d time.Duration) Option {
+	return func(cnf *Config) {
+		cnf.WebIdleTimeout = d
+	}
+}
+
+// OptWebPublicUI sets WebPublicUI flag.
+func OptWebPublicUI(b bool) Option {
+	return func(cnf *Config) {
+		cnf.WebPublicUI = b
+	}
+}
+
+// OptWebPresets sets presets of the web GUI form.
+func OptWebPresets(ps []Preset) Option {
+	return func(cnf *Config) {
+		cnf.WebPresets = ps
+	}
+}
+
+// OptWebReadTimeout sets read timeout of the web server.
+func OptWebReadTimeout(d time.Duration) Option {
+	return func(cnf *Config) {
+		cnf.WebReadTimeout = d
+	}
+}
+
+// OptWebShutdownTimeout sets the time given to the web server for a
+// graceful shutdown.
+func OptWebShutdownTimeout(d time.Duration) Option {
+	return func(cnf *Config) {
+		cnf.WebShutdownTimeout = d
+	}
+}
+
+// OptWebTLSCert sets path to a TLS certificate file.
+func OptWebTLSCert(s string) Option {
+	return func(cnf *Config) {
+		cnf.WebTLSCert = s
+	}
+}
+
+// OptWebTLSKey sets path to a TLS private key file.
+func OptWebTLSKey(s string) Option {
+	return func(cnf *Config) {
+		cnf.WebTLSKey = s
+	}
+}
+
+// OptWebTLSSelfSigned sets WebTLSSelfSigned flag.
+func OptWebTLSSelfSigned(b bool) Option {
+	return func(cnf *Config) {
+		cnf.WebTLSSelfSigned = b
+	}
+}
+
+// OptWebWriteTimeout sets write timeout of the web server.
+func OptWebWriteTimeout(d time.Duration) Option {
+	return func(cnf *Config) {
+		cnf.WebWriteTimeout = d
+	}
+}
+
 // OptWithAllMatches sets WithAllMatches flag.
 func OptWithAllMatches(b bool) Option {
 	return func(cnf *Config) {
@@ -142,6 +430,13 @@ func OptWithUninomialFuzzyMatch(b bool) Option {
 	}
 }
 
+// OptWithin sets a higher taxon that is expected to contain names.
+func OptWithin(s string) Option {
+	return func(cnf *Config) {
+		cnf.Within = s
+	}
+}
+
 // New is a Config constructor that takes external options to
 // update default values to external ones.
 func New(opts ...Option) Config {
@@ -151,6 +446,12 @@ func New(opts ...Option) Config {
 		Batch:             5000,
 		Jobs:              4,
 		NamesNumThreshold: 20,
+
+		VerificationTimeout: 20 * time.Second,
+		WebIdleTimeout:      2 * time.Minute,
+		WebReadTimeout:      5 * time.Minute,
+		WebShutdownTimeout:  30 * time.Second,
+		WebWriteTimeout:     5 * time.Minute,
 	}
 	for _, opt := range opts {
 		opt(&cnf)
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gnames/gnfmt"
 	"github.com/gnames/gnverifier/pkg/config"
@@ -18,6 +19,11 @@ func TestConfigDefault(t *testing.T) {
 	}
 	assert.Equal(t, deflt.Format, cnf.Format)
 	assert.Equal(t, deflt.VerifierURL, cnf.VerifierURL)
+	assert.Equal(t, 20*time.Second, cnf.VerificationTimeout)
+	assert.Equal(t, 5*time.Minute, cnf.WebReadTimeout)
+	assert.Equal(t, 5*time.Minute, cnf.WebWriteTimeout)
+	assert.Equal(t, 2*time.Minute, cnf.WebIdleTimeout)
+	assert.Equal(t, 30*time.Second, cnf.WebShutdownTimeout)
 }
 
 func TestConfigOpts(t *testing.T) {
@@ -27,12 +33,30 @@ func TestConfigOpts(t *testing.T) {
 		Format:      gnfmt.PrettyJSON,
 		DataSources: []int{1, 2, 3},
 		VerifierURL: url,
+		Batch:       100,
 	}
 	assert.Equal(t, updt.Format, cnf.Format)
+	assert.Equal(t, updt.Batch, cnf.Batch)
 	assert.Equal(t, updt.DataSources, cnf.DataSources)
 	assert.Equal(t, updt.VerifierURL, cnf.VerifierURL)
 }
 
+func TestWebBasePath(t *testing.T) {
+	tests := []struct {
+		inp, out string
+	}{
+		{"", ""},
+		{"/", ""},
+		{"/tools/gnverifier/", "/tools/gnverifier"},
+		{"tools/gnverifier", "/tools/gnverifier"},
+		{" /gnv ", "/gnv"},
+	}
+	for _, v := range tests {
+		cnf := config.New(config.OptWebBasePath(v.inp))
+		assert.Equal(t, v.out, cnf.WebBasePath, v.inp)
+	}
+}
+
 type formatTest struct {
 	String string
 	gnfmt.Format
@@ -43,5 +67,28 @@ func opts() []config.Option {
 		config.OptFormat(gnfmt.PrettyJSON),
 		config.OptDataSources([]int{1, 2, 3}),
 		config.OptVerifierURL(url),
+		config.OptBatch(100),
+	}
+}
+
+func TestDedupMode(t *testing.T) {
+	tests := []struct {
+		inp string
+		out config.DedupMode
+		err bool
+	}{
+		{"", config.DedupNone, false},
+		{"none", config.DedupNone, false},
+		{"Exact", config.DedupExact, false},
+		{" normalized ", config.DedupNormalized, false},
+		{"fuzzy", config.DedupNone, true},
+	}
+	for _, v := range tests {
+		res, err := config.NewDedupMode(v.inp)
+		assert.Equal(t, v.err, err != nil, v.inp)
+		assert.Equal(t, v.out, res, v.inp)
 	}
+	assert.Equal(t, "normalized", config.DedupNormalized.String())
+	cnf := config.New(config.OptDedup(config.DedupExact))
+	assert.Equal(t, config.DedupExact, cnf.Dedup)
 }
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DedupMode determines which input names are treated as repeats of each
+// other and verified only once.
+type DedupMode int
+
+const (
+	// DedupNone sends every name for verification.
+	DedupNone DedupMode = iota
+
+	// DedupExact verifies identical name-strings once.
+	DedupExact
+
+	// DedupNormalized verifies name-strings once if they differ only by
+	// whitespace or by letter case.
+	DedupNormalized
+)
+
+var dedupModes = []string{"none", "exact", "normalized"}
+
+// NewDedupMode converts a string ("none", "exact", "normalized") to
+// DedupMode. An empty string means DedupNone.
+func NewDedupMode(s string) (DedupMode, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return DedupNone, nil
+	}
+	for i, v := range dedupModes {
+		if s == v {
+			return DedupMode(i), nil
+		}
+	}
+	return DedupNone, fmt.Errorf(
+		"unknown deduplication mode '%s', use %s", s,
+		strings.Join(dedupModes, ", "),
+	)
+}
+
+// String returns the name of the mode.
+func (d DedupMode) String() string {
+	if d < 0 || int(d) >= len(dedupModes) {
+		return "none"
+	}
+	return dedupModes[d]
+}
@@ -0,0 +1,118 @@
+package gnverifier
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnuuid"
+	"github.com/gnames/gnverifier/pkg/config"
+)
+
+// dedup remembers names of a run, so every unique name is verified only
+// once. It is safe for concurrent use by verification workers.
+type dedup struct {
+	sync.Mutex
+	mode config.DedupMode
+	seen map[string]*dedupEntry
+}
+
+// dedupEntry is the result of a unique name. The done channel is closed
+// when the result is ready.
+type dedupEntry struct {
+	done chan struct{}
+	name vlib.Name
+	err  error
+}
+
+// newDedup returns nil if deduplication is off.
+func newDedup(mode config.DedupMode) *dedup {
+	if mode == config.DedupNone {
+		return nil
+	}
+	return &dedup{mode: mode, seen: make(map[string]*dedupEntry)}
+}
+
+// key returns the string that is the same for repeated names.
+func (dd *dedup) key(name string) string {
+	if dd.mode == config.DedupNormalized {
+		return strings.ToLower(strings.Join(strings.Fields(name), " "))
+	}
+	return name
+}
+
+// split finds entries for names of a batch. It returns names that are
+// new for the run, together with their entries. Names that are seen
+// before reuse existing entries.
+func (dd *dedup) split(names []string) (
+	entries []*dedupEntry,
+	uniq []string,
+	own []*dedupEntry,
+) {
+	dd.Lock()
+	defer dd.Unlock()
+	entries = make([]*dedupEntry, len(names))
+	for i, name := range names {
+		k := dd.key(name)
+		e, ok := dd.seen[k]
+		if !ok {
+			e = &dedupEntry{done: make(chan struct{})}
+			dd.seen[k] = e
+			uniq = append(uniq, name)
+			own = append(own, e)
+		}
+		entries[i] = e
+	}
+	return entries, uniq, own
+}
+
+// verifyDedup verifies names that are new for the run and copies results
+// to every name of the batch, keeping the order. A result of a repeated
+// name has the name-string and ID of that occurrence.
+func (gnv gnverifier) verifyDedup(
+	ctx context.Context,
+	dd *dedup,
+	names []string,
+) ([]vlib.Name, error) {
+	entries, uniq, own := dd.split(names)
+
+	var res []vlib.Name
+	var err error
+	if len(uniq) > 0 {
+		var verif vlib.Output
+		verif, err = gnv.verifier.Verify(ctx, gnv.setParams(uniq))
+		res = verif.Names
+	}
+	for i, e := range own {
+		switch {
+		case err != nil:
+			e.err = err
+		case i < len(res):
+			e.name = res[i]
+		}
+		close(e.done)
+	}
+
+	out := make([]vlib.Name, len(names))
+	for i, e := range entries {
+		select {
+		case <-ctx.Done():
+			return failedNames(names, ctx.Err()), ctx.Err()
+		case <-e.done:
+		}
+		if e.err != nil {
+			if err == nil {
+				err = e.err
+			}
+			out[i] = failedNames(names[i:i+1], e.err)[0]
+			continue
+		}
+		out[i] = e.name
+		if out[i].Name != names[i] {
+			out[i].Name = names[i]
+			out[i].ID = gnuuid.New(names[i]).String()
+		}
+	}
+	return out, err
+}
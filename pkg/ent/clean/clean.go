@@ -0,0 +1,301 @@
+// Package clean prepares input name-strings for verification. It removes
+// noise that often comes from spreadsheets, web pages and field notes and
+// prevents names from matching.
+package clean
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Step is one transformation of a name-string.
+type Step string
+
+const (
+	// Mojibake repairs UTF-8 text that was decoded as Windows-1252,
+	// for example "MÃ¼ller" becomes "Müller".
+	Mojibake Step = "mojibake"
+
+	// HTML removes tags and converts HTML entities to characters,
+	// for example "<i>Bubo</i>&nbsp;bubo" becomes "Bubo bubo".
+	HTML Step = "html"
+
+	// Whitespace converts tabs, non-breaking and other spaces to plain
+	// spaces, removes repeated spaces and trims the string.
+	Whitespace Step = "whitespace"
+
+	// Qualifiers removes uncertainty qualifiers "?", "cf." and "aff.",
+	// for example "Bubo cf. bubo?" becomes "Bubo bubo".
+	Qualifiers Step = "qualifiers"
+
+	// Punctuation removes trailing punctuation, for example
+	// "Bubo bubo;" becomes "Bubo bubo". A period after an abbreviation
+	// of an author, like in "Aus bus L.", is kept.
+	Punctuation Step = "punctuation"
+
+	// Caps converts names written in capital letters to the normal
+	// case, for example "BUBO BUBO L." becomes "Bubo bubo L.". The
+	// authorship keeps its case.
+	Caps Step = "caps"
+)
+
+// Steps returns all steps in the order they are applied.
+func Steps() []Step {
+	return []Step{Mojibake, HTML, Whitespace, Punctuation, Qualifiers, Caps}
+}
+
+// ParseSteps converts names of steps to Steps. The name "all" means all
+// steps, "none" means no steps.
+func ParseSteps(ss []string) ([]Step, error) {
+	var res []Step
+	for _, s := range ss {
+		s = strings.ToLower(strings.TrimSpace(s))
+		switch s {
+		case "", "none":
+			continue
+		case "all":
+			return Steps(), nil
+		}
+		if !slices.Contains(Steps(), Step(s)) {
+			return nil, fmt.Errorf(
+				"unknown cleaning step '%s', use all, none or %s", s,
+				strings.Join(stepNames(), ", "),
+			)
+		}
+		res = append(res, Step(s))
+	}
+	return res, nil
+}
+
+func stepNames() []string {
+	var res []string
+	for _, v := range Steps() {
+		res = append(res, string(v))
+	}
+	return res
+}
+
+// Cleaner applies selected steps to name-strings.
+type Cleaner struct {
+	steps []Step
+}
+
+// New creates a Cleaner. Steps are always applied in the order of Steps,
+// no matter in which order they are given.
+func New(steps ...Step) Cleaner {
+	var res Cleaner
+	for _, v := range Steps() {
+		if slices.Contains(steps, v) {
+			res.steps = append(res.steps, v)
+		}
+	}
+	return res
+}
+
+// Enabled is true if the Cleaner has at least one step.
+func (c Cleaner) Enabled() bool {
+	return len(c.steps) > 0
+}
+
+// Clean returns the cleaned name-string.
+func (c Cleaner) Clean(s string) string {
+	for _, v := range c.steps {
+		switch v {
+		case Mojibake:
+			s = fixMojibake(s)
+		case HTML:
+			s = html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+		case Whitespace:
+			s = strings.Join(strings.FieldsFunc(s, isSpace), " ")
+		case Qualifiers:
+			s = removeQualifiers(s)
+		case Punctuation:
+			s = trimPunctuation(s)
+		case Caps:
+			s = fixCaps(s)
+		}
+	}
+	return s
+}
+
+var htmlTag = regexp.MustCompile(`<[^<>]+>`)
+
+// mojibakeMarks are characters that usually appear when UTF-8 is read
+// as Windows-1252.
+const mojibakeMarks = "ÃÂâ"
+
+func fixMojibake(s string) string {
+	if !strings.ContainsAny(s, mojibakeMarks) {
+		return s
+	}
+	bs, err := charmap.Windows1252.NewEncoder().String(s)
+	if err != nil || !utf8.ValidString(bs) {
+		return s
+	}
+	return bs
+}
+
+func isSpace(r rune) bool {
+	return unicode.IsSpace(r) || r == '\u200b' || r == '\ufeff'
+}
+
+var qualifiers = map[string]struct{}{
+	"?": {}, "cf.": {}, "cf": {}, "aff.": {}, "aff": {},
+}
+
+func removeQualifiers(s string) string {
+	words := strings.Fields(s)
+	res := words[:0]
+	for _, w := range words {
+		if _, ok := qualifiers[strings.ToLower(w)]; ok {
+			continue
+		}
+		w = strings.Trim(w, "?")
+		if w != "" {
+			res = append(res, w)
+		}
+	}
+	return strings.Join(res, " ")
+}
+
+// abbreviations are lowercase words that keep their periods.
+var abbreviations = map[string]struct{}{
+	"sp.": {}, "spp.": {}, "ssp.": {}, "subsp.": {}, "var.": {},
+	"f.": {}, "fil.": {}, "al.": {}, "ex.": {},
+}
+
+// trimPunctuation removes trailing punctuation. In names without lowercase
+// letters every word looks like an abbreviation of an author, so a period
+// is kept there only if the word is in the authorship.
+func trimPunctuation(s string) string {
+	caps := isCaps(s)
+	for {
+		s = strings.TrimRight(s, ",;:!/- ")
+		if !strings.HasSuffix(s, ".") {
+			return s
+		}
+		words := strings.Fields(s)
+		last := words[len(words)-1]
+		if caps {
+			last = strings.ToLower(last)
+		}
+		if _, ok := abbreviations[last]; ok {
+			return s
+		}
+		if caps && len(words)-1 >= authorStart(words) {
+			return s
+		}
+		if !caps && isAuthorAbbr(last) {
+			return s
+		}
+		s = strings.TrimSuffix(s, ".")
+	}
+}
+
+// isAuthorAbbr detects abbreviations of authors, like "L." or "DC.".
+func isAuthorAbbr(w string) bool {
+	w = strings.TrimSuffix(w, ".")
+	r, _ := utf8.DecodeRuneInString(w)
+	if !unicode.IsUpper(r) {
+		return false
+	}
+	for _, r := range w {
+		if !unicode.IsLetter(r) && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// ranks are markers of infraspecific ranks that are written in lowercase.
+var ranks = map[string]struct{}{
+	"var.": {}, "subvar.": {}, "subsp.": {}, "ssp.": {}, "f.": {},
+	"forma": {}, "fo.": {}, "sp.": {}, "spp.": {},
+}
+
+// isCaps is true if a string has at least two letters and all of them
+// are capital.
+func isCaps(s string) bool {
+	var letters int
+	for _, r := range s {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters > 1
+}
+
+// authorStart returns the index of the first word of the authorship in
+// words of a name in capital letters. Such authors look like epithets, so
+// the authorship starts after the genus, the species epithet, and ranks
+// with their epithets. A word with a parenthesis, a comma, an ampersand
+// or a digit, or a short abbreviation like "L." or "DC.", starts the
+// authorship earlier.
+func authorStart(words []string) int {
+	for i, w := range words {
+		if i == 0 {
+			continue
+		}
+		if _, ok := ranks[strings.ToLower(w)]; ok {
+			continue
+		}
+		if strings.ContainsAny(w, "(),&0123456789") || isShortAbbr(w) {
+			return i
+		}
+		if i == 1 {
+			continue
+		}
+		if _, ok := ranks[strings.ToLower(words[i-1])]; ok {
+			continue
+		}
+		return i
+	}
+	return len(words)
+}
+
+// isShortAbbr detects short abbreviations of authors, like "L." or "DC.".
+// Epithets in capital letters with a period by mistake are longer.
+func isShortAbbr(w string) bool {
+	return strings.HasSuffix(w, ".") &&
+		utf8.RuneCountInString(w) <= 3 &&
+		isAuthorAbbr(w)
+}
+
+// fixCaps converts the genus and epithets of a name in capital letters to
+// the normal case. The authorship keeps its case, so abbreviations like
+// "L." or authors like "LINNAEUS" are not changed.
+func fixCaps(s string) string {
+	if !isCaps(s) {
+		return s
+	}
+
+	words := strings.Split(s, " ")
+	start := authorStart(strings.Fields(s))
+	var num int
+	for i, w := range words {
+		if w == "" {
+			continue
+		}
+		if num == start {
+			break
+		}
+		lw := strings.ToLower(w)
+		words[i] = lw
+		if num == 0 {
+			r, size := utf8.DecodeRuneInString(lw)
+			words[i] = string(unicode.ToUpper(r)) + lw[size:]
+		}
+		num++
+	}
+	return strings.Join(words, " ")
+}
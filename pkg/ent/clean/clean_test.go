@@ -0,0 +1,79 @@
+package clean_test
+
+import (
+	"testing"
+
+	"github.com/gnames/gnverifier/pkg/ent/clean"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		step     clean.Step
+		inp, out string
+	}{
+		{clean.Mojibake, "Bubo bubo MÃ¼ller", "Bubo bubo Müller"},
+		{clean.Mojibake, "Aus bus Müller", "Aus bus Müller"},
+		{clean.Mojibake, "Ãrbol", "Ãrbol"},
+		{clean.HTML, "<i>Bubo</i>&nbsp;bubo", "Bubo bubo"},
+		{clean.HTML, "Aus bus Fr&ouml;hlich", "Aus bus Fröhlich"},
+		{clean.Whitespace, "\tBubo  bubo ​", "Bubo bubo"},
+		{clean.Qualifiers, "Bubo cf. bubo", "Bubo bubo"},
+		{clean.Qualifiers, "?Bubo aff bubo?", "Bubo bubo"},
+		{clean.Qualifiers, "Bubo bubo ?", "Bubo bubo"},
+		{clean.Punctuation, "Bubo bubo;", "Bubo bubo"},
+		{clean.Punctuation, "Bubo bubo.,", "Bubo bubo"},
+		{clean.Punctuation, "Aus bus L.", "Aus bus L."},
+		{clean.Punctuation, "Aus sp.", "Aus sp."},
+		{clean.Punctuation, "Aus bus (L.).", "Aus bus (L.)"},
+		{clean.Punctuation, "Aus bus Linn.", "Aus bus Linn."},
+		{clean.Punctuation, "BUBO BUBO.", "BUBO BUBO"},
+		{clean.Punctuation, "BUBO BUBO L.", "BUBO BUBO L."},
+		{clean.Punctuation, "AUS SP.", "AUS SP."},
+		{clean.Caps, "BUBO BUBO", "Bubo bubo"},
+		{clean.Caps, "Bubo BUBO", "Bubo BUBO"},
+		{clean.Caps, "A", "A"},
+		{clean.Caps, "BUBO BUBO L.", "Bubo bubo L."},
+		{clean.Caps, "AUS BUS VAR. CUS DC.", "Aus bus var. cus DC."},
+		{clean.Caps, "BUBO BUBO LINNAEUS 1758", "Bubo bubo LINNAEUS 1758"},
+		{clean.Caps, "BUBO L.", "Bubo L."},
+		{clean.Caps, "POMATOMUS SALTATRIX (LINNAEUS, 1766)",
+			"Pomatomus saltatrix (LINNAEUS, 1766)"},
+	}
+	for _, v := range tests {
+		c := clean.New(v.step)
+		assert.Equal(t, v.out, c.Clean(v.inp), string(v.step)+": "+v.inp)
+	}
+}
+
+func TestCleanAll(t *testing.T) {
+	c := clean.New(clean.Steps()...)
+	assert.True(t, c.Enabled())
+	assert.Equal(t, "Bubo bubo",
+		c.Clean("  <b>BUBO</b>&nbsp;CF. BUBO?; "))
+	assert.Equal(t, "Pomatomus saltatrix (Linnaeus, 1766)",
+		c.Clean("Pomatomus\tsaltatrix (Linnaeus, 1766)."))
+	assert.Equal(t, "Bubo bubo", c.Clean("BUBO BUBO."))
+
+	c = clean.New()
+	assert.False(t, c.Enabled())
+	assert.Equal(t, " BUBO ", c.Clean(" BUBO "))
+}
+
+func TestParseSteps(t *testing.T) {
+	steps, err := clean.ParseSteps([]string{"caps", " HTML "})
+	require.NoError(t, err)
+	assert.Equal(t, []clean.Step{clean.Caps, clean.HTML}, steps)
+
+	steps, err = clean.ParseSteps([]string{"all"})
+	require.NoError(t, err)
+	assert.Equal(t, clean.Steps(), steps)
+
+	steps, err = clean.ParseSteps([]string{"none"})
+	require.NoError(t, err)
+	assert.Empty(t, steps)
+
+	_, err = clean.ParseSteps([]string{"caps", "emoji"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,248 @@
+// Package conflict compares results of verification of a name from
+// several data-sources and finds disagreements that need attention of a
+// curator.
+package conflict
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/resolve"
+)
+
+// Kind is a type of disagreement between data-sources.
+type Kind string
+
+const (
+	// Status means that the name is accepted in one data-source and
+	// a synonym in another.
+	Status Kind = "status"
+
+	// AcceptedName means that data-sources have different accepted names
+	// for the name.
+	AcceptedName Kind = "acceptedName"
+
+	// Kingdom means that data-sources place the name into different
+	// kingdoms.
+	Kingdom Kind = "kingdom"
+
+	// Homonym means that one data-source has several taxa with the
+	// matched name. Such records appear only if all matches are returned.
+	Homonym Kind = "homonym"
+)
+
+// Kinds returns all kinds of conflicts in the order they are checked.
+func Kinds() []Kind {
+	return []Kind{Status, AcceptedName, Kingdom, Homonym}
+}
+
+// Conflict is one disagreement about a name.
+type Conflict struct {
+	// Kind of the conflict.
+	Kind Kind `json:"kind"`
+
+	// Values are the disagreeing values with their data-sources, for
+	// example "1 (COL): Synonym".
+	Values []string `json:"values"`
+}
+
+// Record contains conflicts of one name.
+type Record struct {
+	// Name is the verified name-string.
+	Name string `json:"name"`
+
+	// Conflicts found for the name, empty if data-sources agree.
+	Conflicts []Conflict `json:"conflicts"`
+}
+
+// Find compares results of data-sources for a name. The best result of
+// every source is used for status, accepted name and kingdom; all results
+// of a source are used to find homonyms.
+func Find(ver vlib.Name) Record {
+	res := Record{Name: ver.Name}
+
+	var best []*vlib.ResultData
+	bySource := make(map[int][]*vlib.ResultData)
+	for _, v := range ver.Results {
+		if _, ok := bySource[v.DataSourceID]; !ok {
+			best = append(best, v)
+		}
+		bySource[v.DataSourceID] = append(bySource[v.DataSourceID], v)
+	}
+
+	checks := []struct {
+		kind   Kind
+		values []string
+	}{
+		{Status, statuses(best)},
+		{AcceptedName, acceptedNames(best)},
+		{Kingdom, kingdoms(best)},
+		{Homonym, homonyms(best, bySource)},
+	}
+	for _, v := range checks {
+		if len(v.values) > 0 {
+			res.Conflicts = append(res.Conflicts, Conflict{Kind: v.kind, Values: v.values})
+		}
+	}
+	return res
+}
+
+// statuses finds names that are accepted in one source and synonyms in
+// another.
+func statuses(best []*vlib.ResultData) []string {
+	var acc, syn bool
+	var res []string
+	for _, v := range best {
+		switch v.TaxonomicStatus {
+		case vlib.AcceptedTaxStatus:
+			acc = true
+		case vlib.SynonymTaxStatus:
+			syn = true
+		default:
+			continue
+		}
+		res = append(res, value(v, v.TaxonomicStatus.String()))
+	}
+	if !acc || !syn {
+		return nil
+	}
+	return res
+}
+
+// acceptedNames finds sources with different accepted names.
+func acceptedNames(best []*vlib.ResultData) []string {
+	return differ(best, resolve.AcceptedCanonical)
+}
+
+func kingdoms(best []*vlib.ResultData) []string {
+	return differ(best, kingdom)
+}
+
+// homonyms finds sources with several results for the same matched name
+// that have different accepted names.
+func homonyms(
+	best []*vlib.ResultData,
+	bySource map[int][]*vlib.ResultData,
+) []string {
+	var res []string
+	for _, b := range best {
+		var names []string
+		for _, v := range bySource[b.DataSourceID] {
+			if v.MatchedCanonicalSimple != b.MatchedCanonicalSimple {
+				continue
+			}
+			c := resolve.AcceptedCanonical(v)
+			if c != "" && !slices.Contains(names, c) {
+				names = append(names, c)
+			}
+		}
+		if len(names) > 1 {
+			res = append(res, value(b, strings.Join(names, " | ")))
+		}
+	}
+	return res
+}
+
+// differ returns values of sources if at least two of them are different.
+// Empty values are ignored.
+func differ(
+	best []*vlib.ResultData,
+	field func(*vlib.ResultData) string,
+) []string {
+	var res, seen []string
+	for _, v := range best {
+		f := field(v)
+		if f == "" {
+			continue
+		}
+		if !slices.Contains(seen, f) {
+			seen = append(seen, f)
+		}
+		res = append(res, value(v, f))
+	}
+	if len(seen) < 2 {
+		return nil
+	}
+	return res
+}
+
+// kingdomNames maps names of kingdoms used by some data-sources, for
+// example NCBI, to their common names.
+var kingdomNames = map[string]string{
+	"animalia":      "Animalia",
+	"metazoa":       "Animalia",
+	"plantae":       "Plantae",
+	"viridiplantae": "Plantae",
+	"fungi":         "Fungi",
+	"eumycota":      "Fungi",
+}
+
+// kingdom finds the kingdom in the classification of a result. It is
+// empty if the source does not provide ranks. Known synonyms of kingdoms
+// are replaced by common names, other kingdoms are compared as they are.
+func kingdom(r *vlib.ResultData) string {
+	if r.ClassificationPath == "" || r.ClassificationRanks == "" {
+		return ""
+	}
+	path := strings.Split(r.ClassificationPath, "|")
+	ranks := strings.Split(r.ClassificationRanks, "|")
+	for i, v := range ranks {
+		if !strings.EqualFold(v, "kingdom") || i >= len(path) {
+			continue
+		}
+		k := strings.TrimSpace(path[i])
+		if name, ok := kingdomNames[strings.ToLower(k)]; ok {
+			return name
+		}
+		return k
+	}
+	return ""
+}
+
+func value(r *vlib.ResultData, v string) string {
+	if r.DataSourceTitleShort == "" {
+		return fmt.Sprintf("%d: %s", r.DataSourceID, v)
+	}
+	return fmt.Sprintf("%d (%s): %s", r.DataSourceID, r.DataSourceTitleShort, v)
+}
+
+// Summary counts conflicts of many names.
+type Summary struct {
+	// Names is the number of compared names.
+	Names int
+
+	// ConflictNames is the number of names with at least one conflict.
+	ConflictNames int
+
+	// Kinds is the number of names with every kind of conflict.
+	Kinds map[Kind]int
+}
+
+// Add adds a record to the summary.
+func (s *Summary) Add(r Record) {
+	if s.Kinds == nil {
+		s.Kinds = make(map[Kind]int)
+	}
+	s.Names++
+	if len(r.Conflicts) > 0 {
+		s.ConflictNames++
+	}
+	for _, v := range r.Conflicts {
+		s.Kinds[v.Kind]++
+	}
+}
+
+// String describes the summary for humans.
+func (s Summary) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Compared %d names, %d of them have conflicts.\n",
+		s.Names, s.ConflictNames)
+	for _, k := range Kinds() {
+		if s.Kinds[k] > 0 {
+			fmt.Fprintf(&sb, "  %-14s %d\n", string(k)+":", s.Kinds[k])
+		}
+	}
+	return sb.String()
+}
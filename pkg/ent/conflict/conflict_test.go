@@ -0,0 +1,124 @@
+package conflict_test
+
+import (
+	"testing"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/conflict"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	acc := &vlib.ResultData{
+		DataSourceID:           1,
+		DataSourceTitleShort:   "DS",
+		MatchedCanonicalSimple: "Morus alba",
+		TaxonomicStatus:        vlib.AcceptedTaxStatus,
+		CurrentName:            "Morus alba L.",
+		CurrentCanonicalSimple: "Morus alba",
+		ClassificationPath:     "Plantae|Morus|Morus alba",
+		ClassificationRanks:    "kingdom|genus|species",
+	}
+	acc2 := &vlib.ResultData{
+		DataSourceID:           11,
+		DataSourceTitleShort:   "DS",
+		MatchedCanonicalSimple: "Morus alba",
+		TaxonomicStatus:        vlib.AcceptedTaxStatus,
+		CurrentName:            "Morus alba Linn.",
+		CurrentCanonicalSimple: "Morus alba",
+		ClassificationPath:     "Plantae|Morus|Morus alba",
+		ClassificationRanks:    "kingdom|genus|species",
+	}
+	syn := &vlib.ResultData{
+		DataSourceID:           11,
+		DataSourceTitleShort:   "DS",
+		MatchedCanonicalSimple: "Morus alba",
+		TaxonomicStatus:        vlib.SynonymTaxStatus,
+		CurrentName:            "Morus nigra L.",
+		CurrentCanonicalSimple: "Morus nigra",
+		ClassificationPath:     "Plantae|Morus|Morus nigra",
+		ClassificationRanks:    "kingdom|genus|species",
+	}
+	bird := &vlib.ResultData{
+		DataSourceID:           9,
+		DataSourceTitleShort:   "DS",
+		MatchedCanonicalSimple: "Morus alba",
+		TaxonomicStatus:        vlib.AcceptedTaxStatus,
+		CurrentName:            "Morus bassanus (L.)",
+		CurrentCanonicalSimple: "Morus bassanus",
+		ClassificationPath:     "Animalia|Morus|Morus bassanus",
+		ClassificationRanks:    "kingdom|genus|species",
+	}
+	na := &vlib.ResultData{
+		DataSourceID:           12,
+		DataSourceTitleShort:   "DS",
+		MatchedCanonicalSimple: "Morus alba",
+		TaxonomicStatus:        vlib.UnknownTaxStatus,
+		CurrentName:            "Morus rubra L.",
+		CurrentCanonicalSimple: "Morus rubra",
+	}
+	homonym := &vlib.ResultData{
+		DataSourceID:           1,
+		DataSourceTitleShort:   "DS",
+		MatchedCanonicalSimple: "Morus alba",
+		TaxonomicStatus:        vlib.AcceptedTaxStatus,
+		CurrentName:            "Morus australis Poir.",
+		CurrentCanonicalSimple: "Morus australis",
+		ClassificationPath:     "Plantae|Morus|Morus australis",
+		ClassificationRanks:    "kingdom|genus|species",
+	}
+	ncbi := &vlib.ResultData{
+		DataSourceID:           4,
+		DataSourceTitleShort:   "NCBI",
+		MatchedCanonicalSimple: "Morus alba",
+		TaxonomicStatus:        vlib.AcceptedTaxStatus,
+		CurrentName:            "Morus alba",
+		CurrentCanonicalSimple: "Morus alba",
+		ClassificationPath:     "Eukaryota|Viridiplantae|Streptophyta|Morus|Morus alba",
+		ClassificationRanks:    "superkingdom|kingdom|phylum|genus|species",
+	}
+
+	tests := []struct {
+		msg     string
+		results []*vlib.ResultData
+		kinds   []conflict.Kind
+	}{
+		{"agree", []*vlib.ResultData{acc, acc2}, nil},
+		{"no taxonomy", []*vlib.ResultData{acc, na}, nil},
+		{"synonym", []*vlib.ResultData{acc, syn},
+			[]conflict.Kind{conflict.Status, conflict.AcceptedName}},
+		{"kingdom", []*vlib.ResultData{acc, bird},
+			[]conflict.Kind{conflict.AcceptedName, conflict.Kingdom}},
+		{"kingdom synonyms", []*vlib.ResultData{acc, ncbi}, nil},
+		{"homonym", []*vlib.ResultData{acc, homonym, acc2},
+			[]conflict.Kind{conflict.Homonym}},
+		{"no results", nil, nil},
+	}
+
+	for _, v := range tests {
+		res := conflict.Find(vlib.Name{Name: "Morus alba", Results: v.results})
+		assert.Equal(t, "Morus alba", res.Name, v.msg)
+		var kinds []conflict.Kind
+		for _, c := range res.Conflicts {
+			kinds = append(kinds, c.Kind)
+		}
+		assert.Equal(t, v.kinds, kinds, v.msg)
+	}
+
+	res := conflict.Find(vlib.Name{Results: []*vlib.ResultData{acc, syn}})
+	assert.Equal(t,
+		[]string{"1 (DS): Accepted", "11 (DS): Synonym"}, res.Conflicts[0].Values)
+}
+
+func TestSummary(t *testing.T) {
+	var s conflict.Summary
+	s.Add(conflict.Record{Name: "Aus bus"})
+	s.Add(conflict.Record{Name: "Aus cus", Conflicts: []conflict.Conflict{
+		{Kind: conflict.Status}, {Kind: conflict.Kingdom},
+	}})
+	assert.Equal(t, 2, s.Names)
+	assert.Equal(t, 1, s.ConflictNames)
+	assert.Equal(t,
+		"Compared 2 names, 1 of them have conflicts.\n"+
+			"  status:        1\n  kingdom:       1\n", s.String())
+}
@@ -0,0 +1,114 @@
+// Package curation keeps decisions of curators about verification of
+// names. Decisions are applied to results of later runs, so names that
+// were reviewed once do not need to be reviewed again.
+package curation
+
+import (
+	"fmt"
+	"strings"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+)
+
+// reject is the value of RecordID that rejects all matches of a name.
+const reject = "reject"
+
+// Decision is a decision of a curator about a name-string.
+type Decision struct {
+	// Name is the name-string as it is sent to verification.
+	Name string `json:"name" yaml:"name"`
+
+	// DataSourceID is the data-source of the accepted record.
+	DataSourceID int `json:"dataSourceId,omitempty" yaml:"dataSourceId"`
+
+	// RecordID is the ID of the accepted record in the data-source.
+	RecordID string `json:"recordId,omitempty" yaml:"recordId"`
+
+	// Reject is true if all matches of the name are wrong. In a file it
+	// can also be given as RecordID "reject".
+	Reject bool `json:"reject,omitempty" yaml:"reject"`
+
+	// Curator is the person who made the decision.
+	Curator string `json:"curator,omitempty" yaml:"curator"`
+
+	// Date of the decision.
+	Date string `json:"date,omitempty" yaml:"date"`
+
+	// Note is an optional comment of the curator.
+	Note string `json:"note,omitempty" yaml:"note"`
+}
+
+// String describes the decision in a short form, for example "1:12345"
+// or "reject".
+func (d Decision) String() string {
+	if d.Reject {
+		return reject
+	}
+	return fmt.Sprintf("%d:%s", d.DataSourceID, d.RecordID)
+}
+
+// Apply changes the result of verification according to the decision. A
+// rejected name loses all its matches. For an accepted record the result
+// with the record becomes the best one. Apply returns false if the record
+// is not among results of the name.
+func (d Decision) Apply(ver *vlib.Name) bool {
+	if d.Reject {
+		ver.MatchType = vlib.NoMatch
+		ver.BestResult = nil
+		ver.BestResults = nil
+		ver.Results = nil
+		return true
+	}
+	r := d.find(ver)
+	if r == nil {
+		return false
+	}
+	ver.BestResult = r
+	ver.MatchType = r.MatchType
+	return true
+}
+
+func (d Decision) find(ver *vlib.Name) *vlib.ResultData {
+	rs := append([]*vlib.ResultData{ver.BestResult}, ver.BestResults...)
+	rs = append(rs, ver.Results...)
+	for _, v := range rs {
+		if v != nil && v.DataSourceID == d.DataSourceID && v.RecordID == d.RecordID {
+			return v
+		}
+	}
+	return nil
+}
+
+// Decisions are decisions of curators by name-strings.
+type Decisions map[string]Decision
+
+// New creates Decisions from a list. If a name repeats, the last decision
+// wins.
+func New(ds []Decision) (Decisions, error) {
+	res := make(Decisions, len(ds))
+	for i, v := range ds {
+		v.Name = strings.TrimSpace(v.Name)
+		v.RecordID = strings.TrimSpace(v.RecordID)
+		if strings.EqualFold(v.RecordID, reject) {
+			v.RecordID = ""
+			v.Reject = true
+		}
+		if v.Name == "" {
+			return nil, fmt.Errorf("decision %d has no name", i+1)
+		}
+		if !v.Reject && (v.DataSourceID == 0 || v.RecordID == "") {
+			return nil, fmt.Errorf(
+				"decision for '%s' needs a data-source ID and a record ID, "+
+					"or 'reject'", v.Name,
+			)
+		}
+		res[v.Name] = v
+	}
+	return res, nil
+}
+
+// Find returns the decision about a name-string, if it exists.
+func (ds Decisions) Find(name string) (Decision, bool) {
+	d, ok := ds[strings.TrimSpace(name)]
+	return d, ok
+}
@@ -0,0 +1,100 @@
+package curation_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	best := &vlib.ResultData{DataSourceID: 1, RecordID: "a", MatchType: vlib.Fuzzy}
+	other := &vlib.ResultData{DataSourceID: 11, RecordID: "b", MatchType: vlib.Exact}
+	name := func() vlib.Name {
+		return vlib.Name{
+			Name:       "Bubo bubo",
+			MatchType:  vlib.Fuzzy,
+			BestResult: best,
+			Results:    []*vlib.ResultData{best, other},
+		}
+	}
+
+	ver := name()
+	d := curation.Decision{DataSourceID: 11, RecordID: "b"}
+	assert.True(t, d.Apply(&ver))
+	assert.Equal(t, other, ver.BestResult)
+	assert.Equal(t, vlib.Exact, ver.MatchType)
+	assert.Equal(t, "11:b", d.String())
+
+	ver = name()
+	d = curation.Decision{DataSourceID: 1, RecordID: "b"}
+	assert.False(t, d.Apply(&ver))
+	assert.Equal(t, best, ver.BestResult)
+
+	ver = name()
+	d = curation.Decision{Reject: true}
+	assert.True(t, d.Apply(&ver))
+	assert.Nil(t, ver.BestResult)
+	assert.Empty(t, ver.Results)
+	assert.Equal(t, vlib.NoMatch, ver.MatchType)
+	assert.Equal(t, "reject", d.String())
+}
+
+func TestNew(t *testing.T) {
+	ds, err := curation.New([]curation.Decision{
+		{Name: " Bubo bubo ", DataSourceID: 1, RecordID: "a"},
+		{Name: "Pica pica", RecordID: "Reject"},
+		{Name: "Bubo bubo", DataSourceID: 1, RecordID: "c"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, ds, 2)
+	d, ok := ds.Find("Bubo bubo")
+	assert.True(t, ok)
+	assert.Equal(t, "c", d.RecordID)
+	d, _ = ds.Find("Pica pica")
+	assert.True(t, d.Reject)
+	_, ok = ds.Find("Aus bus")
+	assert.False(t, ok)
+
+	_, err = curation.New([]curation.Decision{{Name: "Bubo bubo", RecordID: "a"}})
+	assert.Error(t, err)
+	_, err = curation.New([]curation.Decision{{DataSourceID: 1, RecordID: "a"}})
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"decisions.csv": "ScientificName,DataSourceId,TaxonId,Curator,Date\n" +
+			"Bubo bubo,1,a,Jane,2026-10-01\n" +
+			"Pica pica,,reject,Jane,2026-10-02\n",
+		"decisions.tsv": "name\tdataSourceId\trecordId\n" +
+			"Bubo bubo\t1\ta\n" +
+			"Pica pica\t\treject\n",
+		"decisions.yaml": "- name: Bubo bubo\n" +
+			"  dataSourceId: 1\n" +
+			"  recordId: a\n" +
+			"- name: Pica pica\n" +
+			"  reject: true\n",
+	}
+	for file, data := range files {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+		ds, err := curation.Load(path)
+		require.NoError(t, err, file)
+		assert.Len(t, ds, 2, file)
+		d, _ := ds.Find("Bubo bubo")
+		assert.Equal(t, "1:a", d.String(), file)
+		d, _ = ds.Find("Pica pica")
+		assert.True(t, d.Reject, file)
+	}
+
+	path := filepath.Join(dir, "bad.csv")
+	require.NoError(t, os.WriteFile(path, []byte("name,curator\nBubo bubo,Jane\n"), 0644))
+	_, err := curation.Load(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package curation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Load reads decisions from a file. Files with ".yaml" or ".yml"
+// extension contain a list of decisions, other files are CSV or TSV with
+// a header.
+func Load(path string) (Decisions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ds []Decision
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		ds, err = loadYAML(f)
+	case ".tsv":
+		ds, err = loadCSV(f, '\t')
+	default:
+		ds, err = loadCSV(f, ',')
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read decisions from %s: %w", path, err)
+	}
+	return New(ds)
+}
+
+func loadYAML(r io.Reader) ([]Decision, error) {
+	var res []Decision
+	if err := yaml.NewDecoder(r).Decode(&res); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return res, nil
+}
+
+// csvFields are names of CSV fields and their aliases. Aliases allow to
+// copy rows from gnverifier output.
+var csvFields = map[string]string{
+	"name":           "name",
+	"scientificname": "name",
+	"datasourceid":   "dataSourceId",
+	"recordid":       "recordId",
+	"taxonid":        "recordId",
+	"curator":        "curator",
+	"date":           "date",
+	"note":           "note",
+}
+
+func loadCSV(r io.Reader, sep rune) ([]Decision, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = sep
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CSV header: %w", err)
+	}
+	col := make(map[string]int)
+	for i, v := range header {
+		if f, ok := csvFields[strings.ToLower(strings.TrimSpace(v))]; ok {
+			col[f] = i
+		}
+	}
+	for _, v := range []string{"name", "recordId"} {
+		if _, ok := col[v]; !ok {
+			return nil, fmt.Errorf("no %s field in CSV header", v)
+		}
+	}
+	get := func(row []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var res []Decision
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CSV: %w", err)
+		}
+		d := Decision{
+			Name:     get(row, "name"),
+			RecordID: get(row, "recordId"),
+			Curator:  get(row, "curator"),
+			Date:     get(row, "date"),
+			Note:     get(row, "note"),
+		}
+		if s := get(row, "dataSourceId"); s != "" {
+			d.DataSourceID, err = strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: data-source ID '%s' is not a number",
+					len(res)+2, s)
+			}
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
@@ -0,0 +1,192 @@
+// Package diff compares results of two verification runs.
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Record is the best match of one name-string from a verification run.
+type Record struct {
+	// Name is the verified name-string.
+	Name string
+
+	// MatchType is the type of the best match.
+	MatchType string
+
+	// MatchedName is the name-string of the best match.
+	MatchedName string
+
+	// CurrentName is the currently accepted name of the best match.
+	CurrentName string
+
+	// TaxonomicStatus of the best match.
+	TaxonomicStatus string
+
+	// DataSourceID is the ID of the data-source of the best match.
+	DataSourceID string
+
+	// DataSourceTitle is the short title of the data-source.
+	DataSourceTitle string
+}
+
+// Fields that are compared between runs.
+const (
+	FieldMatchType       = "matchType"
+	FieldMatchedName     = "matchedName"
+	FieldCurrentName     = "currentName"
+	FieldTaxonomicStatus = "taxonomicStatus"
+	FieldDataSource      = "dataSource"
+
+	// FieldAdded and FieldRemoved mark names that exist only in one of
+	// the runs.
+	FieldAdded   = "added"
+	FieldRemoved = "removed"
+)
+
+// Change describes one difference between runs.
+type Change struct {
+	Name  string `json:"name"`
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Report contains all differences between two runs.
+type Report struct {
+	// Compared is the number of names present in both runs.
+	Compared int `json:"compared"`
+
+	// ChangedNames is the number of names with at least one change.
+	ChangedNames int `json:"changedNames"`
+
+	// Changes are all differences in the order of names of the old run.
+	// Names that were added in the new run are at the end.
+	Changes []Change `json:"changes"`
+}
+
+// Compare finds differences between old and new records. Records are
+// matched by name-strings. If a name-string repeats, its occurrences are
+// matched in order.
+func Compare(old, new []Record) Report {
+	var res Report
+	newIdx := make(map[string][]int)
+	for i, v := range new {
+		newIdx[v.Name] = append(newIdx[v.Name], i)
+	}
+	used := make([]bool, len(new))
+
+	for _, o := range old {
+		idx := newIdx[o.Name]
+		if len(idx) == 0 {
+			res.Changes = append(res.Changes, Change{
+				Name: o.Name, Field: FieldRemoved, Old: o.summary(),
+			})
+			continue
+		}
+		n := new[idx[0]]
+		used[idx[0]] = true
+		newIdx[o.Name] = idx[1:]
+
+		res.Compared++
+		changes := compareRecords(o, n)
+		if len(changes) > 0 {
+			res.ChangedNames++
+			res.Changes = append(res.Changes, changes...)
+		}
+	}
+
+	for i, n := range new {
+		if !used[i] {
+			res.Changes = append(res.Changes, Change{
+				Name: n.Name, Field: FieldAdded, New: n.summary(),
+			})
+		}
+	}
+	return res
+}
+
+func compareRecords(o, n Record) []Change {
+	fields := []struct{ name, old, new string }{
+		{FieldMatchType, o.MatchType, n.MatchType},
+		{FieldMatchedName, o.MatchedName, n.MatchedName},
+		{FieldCurrentName, o.CurrentName, n.CurrentName},
+		{FieldTaxonomicStatus, o.TaxonomicStatus, n.TaxonomicStatus},
+		{FieldDataSource, o.dataSource(), n.dataSource()},
+	}
+	var res []Change
+	for _, v := range fields {
+		if v.old != v.new {
+			res = append(res, Change{Name: o.Name, Field: v.name, Old: v.old, New: v.new})
+		}
+	}
+	return res
+}
+
+func (r Record) dataSource() string {
+	if r.DataSourceID == "" || r.DataSourceID == "0" {
+		return ""
+	}
+	if r.DataSourceTitle == "" {
+		return r.DataSourceID
+	}
+	return fmt.Sprintf("%s (%s)", r.DataSourceID, r.DataSourceTitle)
+}
+
+func (r Record) summary() string {
+	res := r.MatchType
+	if r.MatchedName != "" {
+		res += ": " + r.MatchedName
+	}
+	if ds := r.dataSource(); ds != "" {
+		res += " from " + ds
+	}
+	return res
+}
+
+// Summary describes the report for humans.
+func (r Report) Summary() string {
+	counts := make(map[string]int)
+	for _, v := range r.Changes {
+		counts[v.Field]++
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Compared %d names, %d of them changed.\n",
+		r.Compared, r.ChangedNames)
+	fields := []string{
+		FieldMatchType, FieldMatchedName, FieldCurrentName,
+		FieldTaxonomicStatus, FieldDataSource,
+	}
+	for _, f := range fields {
+		if counts[f] > 0 {
+			fmt.Fprintf(&sb, "  %-16s %d\n", f+":", counts[f])
+		}
+	}
+	if counts[FieldAdded] > 0 || counts[FieldRemoved] > 0 {
+		fmt.Fprintf(&sb, "Names only in the new run: %d, only in the old run: %d.\n",
+			counts[FieldAdded], counts[FieldRemoved])
+	}
+
+	var types []string
+	for _, v := range r.Changes {
+		if v.Field == FieldMatchType {
+			types = append(types, v.Old+" -> "+v.New)
+		}
+	}
+	if len(types) == 0 {
+		return sb.String()
+	}
+	slices.Sort(types)
+	sb.WriteString("Match type changes:\n")
+	for i := 0; i < len(types); {
+		j := i
+		for j < len(types) && types[j] == types[i] {
+			j++
+		}
+		fmt.Fprintf(&sb, "  %s: %d\n", types[i], j-i)
+		i = j
+	}
+	return sb.String()
+}
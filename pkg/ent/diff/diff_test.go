@@ -0,0 +1,207 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/diff"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runs() ([]vlib.Name, []vlib.Name) {
+	bubo := &vlib.ResultData{
+		MatchType:            vlib.Exact,
+		MatchedName:          "Bubo bubo",
+		CurrentName:          "Bubo bubo",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         1,
+		DataSourceTitleShort: "COL",
+	}
+	strix := &vlib.ResultData{
+		MatchType:            vlib.Exact,
+		MatchedName:          "Strix bubo",
+		CurrentName:          "Strix bubo",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         1,
+		DataSourceTitleShort: "COL",
+	}
+	strixSyn := &vlib.ResultData{
+		MatchType:            vlib.Exact,
+		MatchedName:          "Strix bubo",
+		CurrentName:          "Bubo bubo",
+		TaxonomicStatus:      vlib.SynonymTaxStatus,
+		DataSourceID:         1,
+		DataSourceTitleShort: "COL",
+	}
+	pomatomus := &vlib.ResultData{
+		MatchType:            vlib.Fuzzy,
+		MatchedName:          "Pomatomus saltatrix",
+		CurrentName:          "Pomatomus saltatrix",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         1,
+		DataSourceTitleShort: "COL",
+	}
+	pomatomusITIS := &vlib.ResultData{
+		MatchType:            vlib.Fuzzy,
+		MatchedName:          "Pomatomus saltatrix",
+		CurrentName:          "Pomatomus saltatrix",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         3,
+		DataSourceTitleShort: "ITIS",
+	}
+	aus := &vlib.ResultData{
+		MatchType:            vlib.PartialExact,
+		MatchedName:          "Aus",
+		CurrentName:          "Aus",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         1,
+		DataSourceTitleShort: "COL",
+	}
+	gone := &vlib.ResultData{
+		MatchType:            vlib.Exact,
+		MatchedName:          "Gone name",
+		CurrentName:          "Gone name",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         1,
+		DataSourceTitleShort: "COL",
+	}
+	added := &vlib.ResultData{
+		MatchType:            vlib.Exact,
+		MatchedName:          "New name",
+		CurrentName:          "New name",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         1,
+		DataSourceTitleShort: "COL",
+	}
+
+	old := []vlib.Name{
+		{Name: "Bubo bubo", BestResult: bubo, Results: []*vlib.ResultData{bubo}},
+		{Name: "Strix bubo", BestResult: strix},
+		{Name: "Pomatomus saltator", BestResult: pomatomus},
+		{Name: "Aus bus", MatchType: vlib.NoMatch},
+		{Name: "Gone name", BestResult: gone},
+	}
+	new := []vlib.Name{
+		{Name: "Bubo bubo", BestResult: bubo},
+		{Name: "Strix bubo", BestResult: strixSyn},
+		{Name: "Pomatomus saltator", BestResult: pomatomusITIS},
+		{Name: "Aus bus", BestResult: aus},
+		{Name: "New name", BestResult: added},
+	}
+	return old, new
+}
+
+func dump(names []vlib.Name, f gnfmt.Format) string {
+	var res []string
+	if h := output.CSVHeader(f); h != "" {
+		res = append(res, h)
+	}
+	for _, v := range names {
+		res = append(res, output.NameOutput(v, f))
+	}
+	return strings.Join(res, "\n")
+}
+
+func TestCompare(t *testing.T) {
+	oldNames, newNames := runs()
+	formats := []gnfmt.Format{
+		gnfmt.CSV, gnfmt.TSV, gnfmt.CompactJSON, gnfmt.PrettyJSON,
+	}
+	for _, f := range formats {
+		t.Run(f.String(), func(t *testing.T) {
+			old, err := diff.Load(strings.NewReader(dump(oldNames, f)))
+			require.NoError(t, err)
+			require.Len(t, old, 5)
+			new, err := diff.Load(strings.NewReader(dump(newNames, f)))
+			require.NoError(t, err)
+
+			rep := diff.Compare(old, new)
+			assert.Equal(t, 4, rep.Compared)
+			assert.Equal(t, 3, rep.ChangedNames)
+
+			type key struct{ name, field string }
+			changes := make(map[key]diff.Change)
+			for _, v := range rep.Changes {
+				changes[key{v.Name, v.Field}] = v
+			}
+			assert.Len(t, changes, 10)
+			c := changes[key{"Strix bubo", diff.FieldCurrentName}]
+			assert.Equal(t, "Strix bubo", c.Old)
+			assert.Equal(t, "Bubo bubo", c.New)
+			assert.Contains(t, changes, key{"Strix bubo", diff.FieldTaxonomicStatus})
+			c = changes[key{"Pomatomus saltator", diff.FieldDataSource}]
+			assert.Equal(t, "1 (COL)", c.Old)
+			assert.Equal(t, "3 (ITIS)", c.New)
+			c = changes[key{"Aus bus", diff.FieldMatchType}]
+			assert.Equal(t, "NoMatch", c.Old)
+			assert.Equal(t, "PartialExact", c.New)
+			assert.Contains(t, changes, key{"Aus bus", diff.FieldMatchedName})
+			assert.Contains(t, changes, key{"Gone name", diff.FieldRemoved})
+			assert.Contains(t, changes, key{"New name", diff.FieldAdded})
+
+			summary := rep.Summary()
+			assert.Contains(t, summary, "Compared 4 names, 3 of them changed.")
+			assert.Contains(t, summary, "NoMatch -> PartialExact: 1")
+		})
+	}
+}
+
+func TestLoadJSONArray(t *testing.T) {
+	oldNames, _ := runs()
+	enc := gnfmt.GNjson{}
+	bs, err := enc.Encode(oldNames)
+	require.NoError(t, err)
+	res, err := diff.Load(strings.NewReader(string(bs)))
+	require.NoError(t, err)
+	assert.Len(t, res, 5)
+	assert.Equal(t, "Pomatomus saltatrix", res[2].MatchedName)
+}
+
+func TestLoadRepeated(t *testing.T) {
+	best := &vlib.ResultData{
+		MatchType:            vlib.Exact,
+		MatchedName:          "Bubo bubo",
+		CurrentName:          "Bubo bubo",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         1,
+		DataSourceTitleShort: "COL",
+	}
+	itis := &vlib.ResultData{
+		MatchType:            vlib.Exact,
+		MatchedName:          "Bubo bubo",
+		CurrentName:          "Bubo bubo",
+		TaxonomicStatus:      vlib.AcceptedTaxStatus,
+		DataSourceID:         3,
+		DataSourceTitleShort: "ITIS",
+	}
+	bubo := vlib.Name{Name: "Bubo bubo", BestResult: best,
+		Results: []*vlib.ResultData{best, itis}}
+	noMatch := vlib.Name{Name: "Aus bus", MatchType: vlib.NoMatch}
+	names := []vlib.Name{bubo, bubo, noMatch, noMatch}
+
+	for _, f := range []gnfmt.Format{gnfmt.CSV, gnfmt.TSV, gnfmt.CompactJSON} {
+		old, err := diff.Load(strings.NewReader(dump(names, f)))
+		require.NoError(t, err, f.String())
+		require.Len(t, old, 4, f.String())
+		new, err := diff.Load(strings.NewReader(dump(names[1:], f)))
+		require.NoError(t, err, f.String())
+
+		rep := diff.Compare(old, new)
+		assert.Equal(t, 3, rep.Compared, f.String())
+		require.Len(t, rep.Changes, 1, f.String())
+		assert.Equal(t, diff.FieldRemoved, rep.Changes[0].Field, f.String())
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := diff.Load(strings.NewReader("  \n"))
+	assert.Error(t, err)
+	_, err = diff.Load(strings.NewReader("a,b,c\n1,2,3\n"))
+	assert.Error(t, err)
+	_, err = diff.Load(strings.NewReader(`{"name": "Bubo"`))
+	assert.Error(t, err)
+}
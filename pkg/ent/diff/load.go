@@ -0,0 +1,162 @@
+package diff
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+)
+
+// Load reads records from gnverifier output. It detects the format of
+// the output: a JSON array, JSON objects one after another (compact or
+// pretty), or CSV/TSV with a header.
+func Load(r io.Reader) ([]Record, error) {
+	br := bufio.NewReader(r)
+	first, err := firstByte(br)
+	if err != nil {
+		return nil, err
+	}
+	switch first {
+	case '[', '{':
+		return loadJSON(br)
+	default:
+		return loadCSV(br)
+	}
+}
+
+func firstByte(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return 0, errors.New("input is empty")
+		}
+		if err != nil {
+			return 0, err
+		}
+		if b == ' ' || b == '\n' || b == '\r' || b == '\t' {
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
+func loadJSON(r io.Reader) ([]Record, error) {
+	dec := json.NewDecoder(r)
+	var names []vlib.Name
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("cannot decode JSON: %w", err)
+		}
+		var err error
+		if raw[0] == '[' {
+			var batch []vlib.Name
+			err = json.Unmarshal(raw, &batch)
+			names = append(names, batch...)
+		} else {
+			var n vlib.Name
+			err = json.Unmarshal(raw, &n)
+			names = append(names, n)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode verification results: %w", err)
+		}
+	}
+
+	res := make([]Record, len(names))
+	for i, v := range names {
+		res[i] = nameRecord(v)
+	}
+	return res, nil
+}
+
+func nameRecord(n vlib.Name) Record {
+	res := Record{Name: n.Name, MatchType: n.MatchType.String()}
+	br := n.BestResult
+	if br == nil && len(n.Results) > 0 {
+		br = n.Results[0]
+	}
+	if br == nil {
+		return res
+	}
+	res.MatchType = br.MatchType.String()
+	res.MatchedName = br.MatchedName
+	res.CurrentName = br.CurrentName
+	res.TaxonomicStatus = br.TaxonomicStatus.String()
+	res.DataSourceID = strconv.Itoa(br.DataSourceID)
+	res.DataSourceTitle = br.DataSourceTitleShort
+	return res
+}
+
+func loadCSV(br *bufio.Reader) ([]Record, error) {
+	header, err := br.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	sep := ','
+	if strings.Count(header, "\t") > strings.Count(header, ",") {
+		sep = '\t'
+	}
+
+	cr := csv.NewReader(io.MultiReader(strings.NewReader(header), br))
+	cr.Comma = sep
+	cr.FieldsPerRecord = -1
+	if sep == '\t' {
+		cr.LazyQuotes = true
+	}
+	fields, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CSV header: %w", err)
+	}
+	col := make(map[string]int)
+	for i, v := range fields {
+		col[v] = i
+	}
+	for _, v := range []string{"Kind", "MatchType", "ScientificName"} {
+		if _, ok := col[v]; !ok {
+			return nil, fmt.Errorf("input is not gnverifier output, no %s field", v)
+		}
+	}
+	get := func(row []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	var res []Record
+	// rows of one input name follow each other. The first row has the
+	// BestMatch kind, or it is the only row of a name without matches.
+	// Other rows have matches from selected data-sources.
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CSV: %w", err)
+		}
+		name := get(row, "ScientificName")
+		first := get(row, "Kind") == "BestMatch" || get(row, "DataSourceId") == ""
+		if !first && len(res) > 0 && res[len(res)-1].Name == name {
+			continue
+		}
+		res = append(res, Record{
+			Name:            name,
+			MatchType:       get(row, "MatchType"),
+			MatchedName:     get(row, "MatchedName"),
+			CurrentName:     get(row, "CurrentName"),
+			TaxonomicStatus: get(row, "TaxonomicStatus"),
+			DataSourceID:    get(row, "DataSourceId"),
+			DataSourceTitle: get(row, "DataSourceTitle"),
+		})
+	}
+	return res, nil
+}
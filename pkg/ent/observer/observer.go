@@ -0,0 +1,95 @@
+// Package observer provides hooks that report progress of verification
+// to applications that embed gnverifier.
+package observer
+
+import "time"
+
+// Observer receives events of a verification stream. Methods are called
+// from several goroutines at once, so implementations must be safe for
+// concurrent use. They should return quickly, because verification waits
+// for them.
+type Observer interface {
+	// BatchStart is called before a batch is sent for verification.
+	BatchStart(BatchStart)
+
+	// BatchFinish is called when verification of a batch is over, whether
+	// it succeeded or not.
+	BatchFinish(BatchFinish)
+
+	// Retry is called before a failed request for a batch is repeated.
+	Retry(Retry)
+
+	// Done is called once, when all batches are processed.
+	Done(Summary)
+}
+
+// BatchStart describes a batch that is about to be verified.
+type BatchStart struct {
+	// Batch is the number of the batch in the input, starting from 1.
+	Batch int
+
+	// Size is the number of names in the batch.
+	Size int
+}
+
+// BatchFinish describes a verified batch.
+type BatchFinish struct {
+	// Batch is the number of the batch in the input, starting from 1.
+	Batch int
+
+	// Size is the number of names in the batch.
+	Size int
+
+	// Duration is the time spent on the verification of the batch,
+	// including retries.
+	Duration time.Duration
+
+	// Err is the error of verification, or nil.
+	Err error
+}
+
+// Retry describes a repeated request for a batch.
+type Retry struct {
+	// Batch is the number of the batch in the input, starting from 1.
+	Batch int
+
+	// Attempt is the number of the upcoming attempt, starting from 2.
+	Attempt int
+
+	// Err is the error of the previous attempt.
+	Err error
+}
+
+// Summary describes a finished verification stream.
+type Summary struct {
+	// Batches is the number of verified batches.
+	Batches int
+
+	// Names is the number of verified names.
+	Names int
+
+	// FailedBatches is the number of batches that could not be verified.
+	FailedBatches int
+
+	// Duration is the time from the start of the stream.
+	Duration time.Duration
+
+	// Err is the error of the stream, or nil.
+	Err error
+}
+
+// Nop is an Observer that ignores all events. Embed it to implement only
+// some of the methods.
+type Nop struct{}
+
+// BatchStart implements Observer.
+func (Nop) BatchStart(BatchStart) {}
+
+// BatchFinish implements Observer.
+func (Nop) BatchFinish(BatchFinish) {}
+
+// Retry implements Observer.
+func (Nop) Retry(Retry) {}
+
+// Done implements Observer.
+func (Nop) Done(Summary) {}
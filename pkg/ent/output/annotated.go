@@ -0,0 +1,119 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
+	"github.com/gnames/gnverifier/pkg/ent/within"
+)
+
+// Columns are optional fields of CSV output.
+type Columns struct {
+	// Input adds the name-string before cleaning as the first field.
+	Input bool
+
+	// Within adds the relation of the best result to the Within taxon,
+	// so results that were promoted because of the taxon can be told
+	// from the best matches of the service.
+	Within bool
+
+	// Decision adds a decision of a curator as the last fields.
+	Decision bool
+}
+
+// AnnotatedName is the result of verification of a name-string with data
+// that vlib.Name does not have.
+type AnnotatedName struct {
+	// Input is the name-string before cleaning.
+	Input string `json:"input,omitempty"`
+
+	vlib.Name
+
+	// Within tells how the best result relates to the Within taxon. It is
+	// "promoted" if the best result was chosen because of the taxon.
+	Within within.Status `json:"within,omitempty"`
+
+	// Decision of a curator that was applied to the result.
+	Decision *curation.Decision `json:"decision,omitempty"`
+}
+
+// AnnotatedHeader returns the header of CSV output of annotated names. It
+// is the same as CSVHeader with fields added by cols.
+func AnnotatedHeader(f gnfmt.Format, cols Columns) string {
+	return annotateHeader(CSVHeader(f), f, cols)
+}
+
+// AnnotatedNameOutput converts an annotated result of verification into
+// the required format. CSV rows start with the original string and end
+// with the Within status and the decision, if cols ask for them. JSON
+// output has them in the "input", "within" and "decision" fields.
+func AnnotatedNameOutput(res AnnotatedName, f gnfmt.Format, cols Columns) string {
+	switch f {
+	case gnfmt.CSV:
+		return annotatedCSV(res, ',', cols)
+	case gnfmt.TSV:
+		return annotatedCSV(res, '\t', cols)
+	case gnfmt.CompactJSON, gnfmt.PrettyJSON:
+		enc := gnfmt.GNjson{Pretty: f == gnfmt.PrettyJSON}
+		bs, _ := enc.Encode(res)
+		return string(bs)
+	}
+	return "N/A"
+}
+
+func annotatedCSV(res AnnotatedName, sep rune, cols Columns) string {
+	rows := csvRows(res.Name, sep)
+	for i := range rows {
+		rows[i] = annotateRow(rows[i], annotation{
+			input: res.Input, within: res.Within, decision: res.Decision,
+		}, sep, cols)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func annotateHeader(header string, f gnfmt.Format, cols Columns) string {
+	if header == "" {
+		return ""
+	}
+	sep := ","
+	if f == gnfmt.TSV {
+		sep = "\t"
+	}
+	if cols.Input {
+		header = "InputName" + sep + header
+	}
+	if cols.Within {
+		header += sep + "Within"
+	}
+	if cols.Decision {
+		header += sep + strings.Join([]string{"Decision", "Curator", "DecisionDate"}, sep)
+	}
+	return header
+}
+
+// annotation are fields that are added to a CSV row.
+type annotation struct {
+	input    string
+	within   within.Status
+	decision *curation.Decision
+}
+
+// annotateRow adds fields to a CSV row that is already formatted.
+func annotateRow(row string, a annotation, sep rune, cols Columns) string {
+	if cols.Input {
+		row = gnfmt.ToCSV([]string{a.input}, sep) + string(sep) + row
+	}
+	if cols.Within {
+		row += string(sep) + string(a.within)
+	}
+	if cols.Decision {
+		fields := []string{"", "", ""}
+		if d := a.decision; d != nil {
+			fields = []string{d.String(), d.Curator, d.Date}
+		}
+		row += string(sep) + gnfmt.ToCSV(fields, sep)
+	}
+	return row
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnverifier/pkg/ent/conflict"
+)
+
+// ConflictsHeader returns the header of CSV output of conflicts.
+func ConflictsHeader(f gnfmt.Format) string {
+	header := []string{"ScientificName", "Conflict", "Values"}
+	switch f {
+	case gnfmt.CSV:
+		return gnfmt.ToCSV(header, ',')
+	case gnfmt.TSV:
+		return gnfmt.ToCSV(header, '\t')
+	default:
+		return ""
+	}
+}
+
+// ConflictsOutput converts conflicts of a name into CSV, TSV or JSON. CSV
+// has one row per conflict, values of data-sources are separated by "; ".
+// The output is empty if there are no conflicts.
+func ConflictsOutput(rec conflict.Record, f gnfmt.Format) string {
+	if len(rec.Conflicts) == 0 {
+		return ""
+	}
+	switch f {
+	case gnfmt.CSV, gnfmt.TSV:
+		sep := ','
+		if f == gnfmt.TSV {
+			sep = '\t'
+		}
+		res := make([]string, len(rec.Conflicts))
+		for i, v := range rec.Conflicts {
+			res[i] = gnfmt.ToCSV([]string{
+				rec.Name, string(v.Kind), strings.Join(v.Values, "; "),
+			}, sep)
+		}
+		return strings.Join(res, "\n")
+	case gnfmt.CompactJSON, gnfmt.PrettyJSON:
+		enc := gnfmt.GNjson{Pretty: f == gnfmt.PrettyJSON}
+		res, _ := enc.Encode(rec)
+		return string(res)
+	}
+	return "N/A"
+}
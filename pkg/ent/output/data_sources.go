@@ -0,0 +1,115 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+)
+
+// DataSourcesOutput converts a list of data-sources into CSV, TSV or JSON.
+// For other formats it returns a table aligned for a terminal.
+func DataSourcesOutput(dss []vlib.DataSource, f gnfmt.Format) string {
+	switch f {
+	case gnfmt.CSV, gnfmt.TSV:
+		sep := ','
+		if f == gnfmt.TSV {
+			sep = '\t'
+		}
+		res := []string{gnfmt.ToCSV(dataSourceHeader(), sep)}
+		for i := range dss {
+			res = append(res, gnfmt.ToCSV(dataSourceRow(dss[i]), sep))
+		}
+		return strings.Join(res, "\n")
+	case gnfmt.CompactJSON, gnfmt.PrettyJSON:
+		enc := gnfmt.GNjson{Pretty: f == gnfmt.PrettyJSON}
+		res, _ := enc.Encode(dss)
+		return string(res)
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tRECORDS\tCURATION\tUPDATED\tOUTLINK")
+	for _, ds := range dss {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			ds.ID, ds.TitleShort, humanize.Comma(int64(ds.RecordCount)),
+			ds.Curation, ds.UpdatedAt, yesNo(ds.IsOutlinkReady),
+		)
+	}
+	_ = w.Flush()
+	return buf.String()
+}
+
+// DataSourceOutput converts full metadata of a data-source into CSV, TSV
+// or JSON. For other formats it returns one field per line.
+func DataSourceOutput(ds vlib.DataSource, f gnfmt.Format) string {
+	switch f {
+	case gnfmt.CSV, gnfmt.TSV:
+		return DataSourcesOutput([]vlib.DataSource{ds}, f)
+	case gnfmt.CompactJSON, gnfmt.PrettyJSON:
+		enc := gnfmt.GNjson{Pretty: f == gnfmt.PrettyJSON}
+		res, _ := enc.Encode(ds)
+		return string(res)
+	}
+
+	fields := []struct{ name, value string }{
+		{"ID", strconv.Itoa(ds.ID)},
+		{"UUID", ds.UUID},
+		{"Title", ds.Title},
+		{"Short title", ds.TitleShort},
+		{"Version", ds.Version},
+		{"Release date", ds.RevisionDate},
+		{"DOI", ds.DOI},
+		{"Citation", ds.Citation},
+		{"Authors", ds.Authors},
+		{"Description", ds.Description},
+		{"Website", ds.WebsiteURL},
+		{"Outlink URL", ds.OutlinkURL},
+		{"Outlink ready", yesNo(ds.IsOutlinkReady)},
+		{"Curation", ds.Curation.String()},
+		{"Taxonomic data", yesNo(ds.HasTaxonData)},
+		{"Records", humanize.Comma(int64(ds.RecordCount))},
+		{"Updated", ds.UpdatedAt},
+	}
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	for _, v := range fields {
+		if v.value == "" {
+			continue
+		}
+		fmt.Fprintf(w, "%s:\t%s\n", v.name, v.value)
+	}
+	_ = w.Flush()
+	return buf.String()
+}
+
+func dataSourceHeader() []string {
+	return []string{"Id", "Title", "TitleShort", "RecordCount", "Curation",
+		"UpdatedAt", "ReleaseDate", "IsOutlinkReady", "HasTaxonData"}
+}
+
+func dataSourceRow(ds vlib.DataSource) []string {
+	return []string{
+		strconv.Itoa(ds.ID),
+		ds.Title,
+		ds.TitleShort,
+		strconv.Itoa(ds.RecordCount),
+		ds.Curation.String(),
+		ds.UpdatedAt,
+		ds.RevisionDate,
+		strconv.FormatBool(ds.IsOutlinkReady),
+		strconv.FormatBool(ds.HasTaxonData),
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
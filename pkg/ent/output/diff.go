@@ -0,0 +1,30 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnverifier/pkg/ent/diff"
+)
+
+// DiffOutput converts a report about differences between two verification
+// runs into CSV, TSV or JSON. CSV has one row per change.
+func DiffOutput(rep diff.Report, f gnfmt.Format) string {
+	switch f {
+	case gnfmt.CSV, gnfmt.TSV:
+		sep := ','
+		if f == gnfmt.TSV {
+			sep = '\t'
+		}
+		res := []string{gnfmt.ToCSV([]string{"Name", "Field", "Old", "New"}, sep)}
+		for _, v := range rep.Changes {
+			res = append(res, gnfmt.ToCSV([]string{v.Name, v.Field, v.Old, v.New}, sep))
+		}
+		return strings.Join(res, "\n")
+	case gnfmt.CompactJSON, gnfmt.PrettyJSON:
+		enc := gnfmt.GNjson{Pretty: f == gnfmt.PrettyJSON}
+		res, _ := enc.Encode(rep)
+		return string(res)
+	}
+	return "N/A"
+}
@@ -62,6 +62,10 @@ func CSVHeader(f gnfmt.Format) string {
 }
 
 func csvOutput(ver vlib.Name, sep rune) string {
+	return strings.Join(csvRows(ver, sep), "\n")
+}
+
+func csvRows(ver vlib.Name, sep rune) []string {
 	var res []string
 	if ver.BestResult != nil {
 		best := csvRow(ver, -1, sep)
@@ -73,8 +77,7 @@ func csvOutput(ver vlib.Name, sep rune) string {
 		pref := csvRow(ver, i, sep)
 		res = append(res, pref)
 	}
-
-	return strings.Join(res, "\n")
+	return res
 }
 
 func csvEmptyRow(ver vlib.Name, sep rune) string {
@@ -90,7 +93,8 @@ func csvRow(ver vlib.Name, prefIndex int, sep rune) string {
 	res := ver.BestResult
 
 	if prefIndex > -1 {
-		if prefIndex > 0 {
+		// only the first row of a name is its best match.
+		if prefIndex > 0 || ver.BestResult != nil {
 			kind = sortedMatch
 		}
 		res = ver.Results[prefIndex]
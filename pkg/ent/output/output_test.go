@@ -9,7 +9,11 @@ import (
 	"github.com/dnaeon/go-vcr/cassette"
 	"github.com/gnames/gnfmt"
 	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/conflict"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
 	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/ent/resolve"
+	"github.com/gnames/gnverifier/pkg/ent/within"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 )
@@ -96,3 +100,236 @@ func verifications(t *testing.T) vlib.Output {
 	assert.Nil(t, err)
 	return res
 }
+
+func TestDataSourcesOutput(t *testing.T) {
+	dss := []vlib.DataSource{
+		{ID: 1, Title: "Catalogue of Life", TitleShort: "CoL",
+			Curation: vlib.Curated, RecordCount: 4_500_000,
+			UpdatedAt: "2026-03-01", IsOutlinkReady: true},
+		{ID: 170, Title: "Arctos", TitleShort: "Arctos",
+			Curation: vlib.NotCurated, RecordCount: 120},
+	}
+
+	res := output.DataSourcesOutput(dss, gnfmt.FormatNone)
+	lines := strings.Split(strings.TrimSpace(res), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], "ID"))
+	assert.Contains(t, lines[1], "4,500,000")
+	assert.Contains(t, lines[1], "Curated")
+	assert.Contains(t, lines[1], "yes")
+	assert.Contains(t, lines[2], "NotCurated")
+
+	res = output.DataSourcesOutput(dss, gnfmt.CSV)
+	lines = strings.Split(res, "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "1,Catalogue of Life,CoL,4500000,Curated,2026-03-01,,true,false",
+		lines[1])
+
+	res = output.DataSourcesOutput(dss, gnfmt.CompactJSON)
+	assert.True(t, strings.HasPrefix(res, `[{"id":1,`))
+
+	res = output.DataSourceOutput(dss[0], gnfmt.FormatNone)
+	assert.Contains(t, res, "Title:")
+	assert.Contains(t, res, "Catalogue of Life")
+	assert.NotContains(t, res, "DOI:")
+
+	res = output.DataSourceOutput(dss[0], gnfmt.CompactJSON)
+	assert.True(t, strings.HasPrefix(res, `{"id":1,`))
+}
+
+func TestCSVKind(t *testing.T) {
+	best := &vlib.ResultData{
+		MatchType:    vlib.Exact,
+		MatchedName:  "Bubo bubo",
+		DataSourceID: 1,
+	}
+	itis := &vlib.ResultData{
+		MatchType:    vlib.Exact,
+		MatchedName:  "Bubo bubo",
+		DataSourceID: 3,
+	}
+	tests := []struct {
+		msg   string
+		input vlib.Name
+		kinds []string
+	}{
+		{
+			msg: "best and preferred",
+			input: vlib.Name{
+				Name:       "Bubo bubo",
+				BestResult: best,
+				Results:    []*vlib.ResultData{best, itis},
+			},
+			kinds: []string{"BestMatch", "SortedMatch", "SortedMatch"},
+		},
+		{
+			msg: "preferred only",
+			input: vlib.Name{
+				Name:    "Bubo bubo",
+				Results: []*vlib.ResultData{best, itis},
+			},
+			kinds: []string{"BestMatch", "SortedMatch"},
+		},
+		{
+			msg:   "no match",
+			input: vlib.Name{Name: "Aus bus", MatchType: vlib.NoMatch},
+			kinds: []string{"SortedMatch"},
+		},
+	}
+
+	for _, v := range tests {
+		rows := strings.Split(output.NameOutput(v.input, gnfmt.CSV), "\n")
+		kinds := make([]string, len(rows))
+		for i := range rows {
+			kinds[i], _, _ = strings.Cut(rows[i], ",")
+		}
+		assert.Equal(t, v.kinds, kinds, v.msg)
+	}
+}
+
+func TestQueryOutput(t *testing.T) {
+	verifs := verifications(t).Names
+	res := output.QueryResult{
+		Query: "g:Bubo sp:bubo",
+		Names: verifs[:2],
+	}
+
+	header := output.QueryHeader(gnfmt.CSV)
+	assert.True(t, strings.HasPrefix(header, "Query,Kind,"))
+	assert.Equal(t, "", output.QueryHeader(gnfmt.CompactJSON))
+
+	csv := output.QueryOutput(res, gnfmt.CSV)
+	rows := strings.Split(csv, "\n")
+	expected := strings.Count(output.NameOutput(verifs[0], gnfmt.CSV), "\n") +
+		strings.Count(output.NameOutput(verifs[1], gnfmt.CSV), "\n") + 2
+	assert.Equal(t, expected, len(rows))
+	for _, v := range rows {
+		assert.True(t, strings.HasPrefix(v, "g:Bubo sp:bubo,"), v)
+	}
+
+	tsv := output.QueryOutput(
+		output.QueryResult{Query: "g:Aus", Error: "timeout"}, gnfmt.TSV,
+	)
+	assert.True(t, strings.HasPrefix(tsv, "g:Aus\tSortedMatch\t"))
+	assert.True(t, strings.HasSuffix(tsv, "\ttimeout"))
+
+	json := output.QueryOutput(res, gnfmt.CompactJSON)
+	assert.True(t, strings.HasPrefix(json, `{"query":"g:Bubo sp:bubo","names":[`))
+}
+
+func TestAnnotatedNameOutput(t *testing.T) {
+	verif := verifications(t).Names[0]
+	input := strings.ToUpper(verif.Name) + ";"
+	res := output.AnnotatedName{Input: input, Name: verif}
+	cols := output.Columns{Input: true}
+
+	header := output.AnnotatedHeader(gnfmt.TSV, cols)
+	assert.True(t, strings.HasPrefix(header, "InputName\tKind\t"))
+	assert.Equal(t, "", output.AnnotatedHeader(gnfmt.PrettyJSON, cols))
+
+	csv := output.AnnotatedNameOutput(res, gnfmt.CSV, cols)
+	rows := strings.Split(csv, "\n")
+	assert.Equal(t,
+		len(strings.Split(output.NameOutput(verif, gnfmt.CSV), "\n")), len(rows))
+	for _, v := range rows {
+		assert.True(t, strings.HasPrefix(v, `"`+input+`",`), v)
+	}
+
+	json := output.AnnotatedNameOutput(res, gnfmt.CompactJSON, cols)
+	assert.True(t, strings.HasPrefix(json, `{"input":"`+input+`",`), json)
+	assert.Contains(t, json, `"name":"`+verif.Name+`"`)
+	assert.Contains(t, json, `"matchType":`)
+	assert.NotContains(t, json, `"decision":`)
+}
+
+func TestAnnotatedDecision(t *testing.T) {
+	verif := verifications(t).Names[0]
+	d := curation.Decision{
+		DataSourceID: 1, RecordID: "a", Curator: "Jane", Date: "2026-10-01",
+	}
+	res := output.AnnotatedName{Name: verif, Decision: &d}
+	cols := output.Columns{Decision: true}
+
+	header := output.AnnotatedHeader(gnfmt.CSV, cols)
+	assert.True(t, strings.HasPrefix(header, "Kind,"))
+	assert.True(t, strings.HasSuffix(header, ",Error,Decision,Curator,DecisionDate"))
+
+	csv := output.AnnotatedNameOutput(res, gnfmt.CSV, cols)
+	for _, v := range strings.Split(csv, "\n") {
+		assert.True(t, strings.HasSuffix(v, ",1:a,Jane,2026-10-01"), v)
+	}
+	res.Decision = nil
+	csv = output.AnnotatedNameOutput(res, gnfmt.TSV, cols)
+	assert.True(t, strings.HasSuffix(csv, "\t\t\t"), csv)
+
+	res.Decision = &d
+	json := output.AnnotatedNameOutput(res, gnfmt.CompactJSON, cols)
+	assert.Contains(t, json, `"decision":{"name":"","dataSourceId":1,"recordId":"a",`)
+}
+
+func TestAnnotatedWithin(t *testing.T) {
+	verif := verifications(t).Names[0]
+	res := output.AnnotatedName{Name: verif, Within: within.Promoted}
+	cols := output.Columns{Within: true, Decision: true}
+
+	header := output.AnnotatedHeader(gnfmt.TSV, cols)
+	assert.True(t, strings.HasSuffix(header, "\tError\tWithin\tDecision\tCurator\tDecisionDate"))
+
+	tsv := output.AnnotatedNameOutput(res, gnfmt.TSV, cols)
+	for _, v := range strings.Split(tsv, "\n") {
+		assert.True(t, strings.HasSuffix(v, "\tpromoted\t\t\t"), v)
+	}
+	json := output.AnnotatedNameOutput(res, gnfmt.CompactJSON, cols)
+	assert.Contains(t, json, `"within":"promoted"`)
+}
+
+func TestResolutionOutput(t *testing.T) {
+	verif := verifications(t).Names[0]
+	res := resolve.New(verif, nil)
+	input := output.Columns{Input: true}
+
+	header := output.ResolutionHeader(gnfmt.CSV, output.Columns{})
+	assert.True(t, strings.HasPrefix(header, "ScientificName,DataSourceId,"))
+	header = output.ResolutionHeader(gnfmt.TSV, input)
+	assert.True(t, strings.HasPrefix(header, "InputName\tScientificName\t"))
+	assert.Equal(t, "", output.ResolutionHeader(gnfmt.CompactJSON, input))
+
+	csv := output.ResolutionOutput(res, gnfmt.CSV, output.Columns{})
+	rows := strings.Split(csv, "\n")
+	assert.Equal(t, len(res.Sources), len(rows))
+	name := gnfmt.ToCSV([]string{verif.Name}, ',')
+	assert.True(t, strings.HasPrefix(rows[0], name+","), rows[0])
+	assert.Equal(t, 9, len(strings.Split(output.ResolutionHeader(gnfmt.TSV, output.Columns{}), "\t")))
+
+	res.Input = "input"
+	csv = output.ResolutionOutput(res, gnfmt.TSV, input)
+	assert.True(t, strings.HasPrefix(csv, "input\t"+verif.Name+"\t"), csv)
+
+	empty := resolve.New(vlib.Name{Name: "NotName"}, nil)
+	csv = output.ResolutionOutput(empty, gnfmt.CSV, output.Columns{})
+	assert.Equal(t, "NotName,,,,,,,false,", csv)
+
+	json := output.ResolutionOutput(res, gnfmt.CompactJSON, input)
+	assert.True(t, strings.HasPrefix(json, `{"input":"input","name":`), json)
+	assert.Contains(t, json, `"acceptedName":`)
+}
+
+func TestConflictsOutput(t *testing.T) {
+	rec := conflict.Record{Name: "Morus alba"}
+	assert.Equal(t, "", output.ConflictsOutput(rec, gnfmt.CSV))
+
+	rec.Conflicts = []conflict.Conflict{
+		{Kind: conflict.Status, Values: []string{"1: Accepted", "11: Synonym"}},
+		{Kind: conflict.Kingdom, Values: []string{"1: Plantae", "9: Animalia"}},
+	}
+	assert.Equal(t, "ScientificName\tConflict\tValues",
+		output.ConflictsHeader(gnfmt.TSV))
+	assert.Equal(t,
+		"Morus alba,status,1: Accepted; 11: Synonym\n"+
+			"Morus alba,kingdom,1: Plantae; 9: Animalia",
+		output.ConflictsOutput(rec, gnfmt.CSV))
+
+	json := output.ConflictsOutput(rec, gnfmt.CompactJSON)
+	assert.True(t, strings.HasPrefix(json,
+		`{"name":"Morus alba","conflicts":[{"kind":"status",`), json)
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+)
+
+// QueryResult contains names found by one advanced search query.
+type QueryResult struct {
+	// Query is the originating search query.
+	Query string `json:"query"`
+
+	// Names are the results of the search.
+	Names []vlib.Name `json:"names"`
+
+	// Error is a message of a failed search.
+	Error string `json:"error,omitempty"`
+}
+
+// QueryHeader returns the header of CSV output of search queries. It is
+// the same as CSVHeader with an additional first "Query" field.
+func QueryHeader(f gnfmt.Format) string {
+	header := CSVHeader(f)
+	switch f {
+	case gnfmt.CSV:
+		return "Query," + header
+	case gnfmt.TSV:
+		return "Query\t" + header
+	default:
+		return ""
+	}
+}
+
+// QueryOutput converts results of a search query into the required format.
+// CSV rows start with the query, JSON output contains the query and all
+// names found by it.
+func QueryOutput(res QueryResult, f gnfmt.Format) string {
+	switch f {
+	case gnfmt.CSV:
+		return queryCSV(res, ',')
+	case gnfmt.TSV:
+		return queryCSV(res, '\t')
+	case gnfmt.CompactJSON, gnfmt.PrettyJSON:
+		enc := gnfmt.GNjson{Pretty: f == gnfmt.PrettyJSON}
+		bs, _ := enc.Encode(res)
+		return string(bs)
+	}
+	return "N/A"
+}
+
+func queryCSV(res QueryResult, sep rune) string {
+	query := gnfmt.ToCSV([]string{res.Query}, sep) + string(sep)
+	var rows []string
+	if len(res.Names) == 0 {
+		rows = []string{csvEmptyRow(vlib.Name{Error: res.Error}, sep)}
+	}
+	for _, v := range res.Names {
+		rows = append(rows, csvRows(v, sep)...)
+	}
+	for i := range rows {
+		rows[i] = query + rows[i]
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gnames/gnfmt"
+	"github.com/gnames/gnverifier/pkg/ent/resolve"
+)
+
+// ResolutionHeader returns the header of CSV output of accepted names
+// with fields added by cols.
+func ResolutionHeader(f gnfmt.Format, cols Columns) string {
+	header := []string{
+		"ScientificName", "DataSourceId", "DataSourceTitle", "MatchedName",
+		"TaxonomicStatus", "AcceptedName", "AcceptedRecordId", "Disagree",
+		"Error",
+	}
+	switch f {
+	case gnfmt.CSV:
+		return annotateHeader(gnfmt.ToCSV(header, ','), f, cols)
+	case gnfmt.TSV:
+		return annotateHeader(gnfmt.ToCSV(header, '\t'), f, cols)
+	default:
+		return ""
+	}
+}
+
+// ResolutionOutput converts accepted names of an input into CSV, TSV or
+// JSON. CSV has one row per data-source, or one row with empty fields if
+// there are no matches.
+func ResolutionOutput(
+	res resolve.Resolution,
+	f gnfmt.Format,
+	cols Columns,
+) string {
+	switch f {
+	case gnfmt.CSV:
+		return resolutionCSV(res, ',', cols)
+	case gnfmt.TSV:
+		return resolutionCSV(res, '\t', cols)
+	case gnfmt.CompactJSON, gnfmt.PrettyJSON:
+		enc := gnfmt.GNjson{Pretty: f == gnfmt.PrettyJSON}
+		bs, _ := enc.Encode(res)
+		return string(bs)
+	}
+	return "N/A"
+}
+
+func resolutionCSV(res resolve.Resolution, sep rune, cols Columns) string {
+	disagree := strconv.FormatBool(res.Disagree)
+	var rows []string
+	row := func(s []string) {
+		r := gnfmt.ToCSV(s, sep)
+		a := annotation{input: res.Input, decision: res.Decision}
+		rows = append(rows, annotateRow(r, a, sep, cols))
+	}
+
+	if len(res.Sources) == 0 {
+		row([]string{res.Name, "", "", "", "", "", "", disagree, res.Error})
+	}
+	for _, v := range res.Sources {
+		row([]string{
+			res.Name, strconv.Itoa(v.DataSourceID), v.DataSourceTitle,
+			v.MatchedName, v.TaxonomicStatus, v.AcceptedName,
+			v.AcceptedRecordID, disagree, res.Error,
+		})
+	}
+	return strings.Join(rows, "\n")
+}
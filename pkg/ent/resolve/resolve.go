@@ -0,0 +1,136 @@
+// Package resolve maps results of verification to accepted names. Every
+// input name gets one accepted name per data-source, which helps to
+// harmonize names of occurrence datasets.
+package resolve
+
+import (
+	"slices"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
+)
+
+// Source is the accepted name of an input according to one data-source.
+type Source struct {
+	// DataSourceID is the ID of the data-source.
+	DataSourceID int `json:"dataSourceId"`
+
+	// DataSourceTitle is the short title of the data-source.
+	DataSourceTitle string `json:"dataSourceTitle"`
+
+	// MatchedName is the name-string that matched the input.
+	MatchedName string `json:"matchedName"`
+
+	// TaxonomicStatus of the matched name: "Accepted", "Synonym" or "N/A".
+	TaxonomicStatus string `json:"taxonomicStatus"`
+
+	// AcceptedName is the currently accepted name of the matched name.
+	AcceptedName string `json:"acceptedName"`
+
+	// AcceptedRecordID is the ID of the accepted name in the data-source.
+	AcceptedRecordID string `json:"acceptedRecordId"`
+
+	// canonical of the accepted name, used to compare sources.
+	canonical string
+}
+
+// Resolution contains accepted names of one input.
+type Resolution struct {
+	// Input is the name-string before cleaning. It is empty if names
+	// were not cleaned.
+	Input string `json:"input,omitempty"`
+
+	// Name is the verified name-string.
+	Name string `json:"name"`
+
+	// Sources are accepted names, one per data-source.
+	Sources []Source `json:"sources"`
+
+	// Disagree is true if data-sources with taxonomic data have different
+	// accepted names for the input.
+	Disagree bool `json:"disagree"`
+
+	// Error of verification, if any.
+	Error string `json:"error,omitempty"`
+
+	// Decision of a curator that was applied to the input, if any.
+	Decision *curation.Decision `json:"decision,omitempty"`
+}
+
+// New creates a Resolution from a result of verification. If dataSources
+// are given (and are not [0]), only these sources are used, in the same
+// order. Otherwise sources follow the order of results. If there are
+// several results from one source, the best one is used.
+func New(ver vlib.Name, dataSources []int) Resolution {
+	res := Resolution{Name: ver.Name, Error: ver.Error}
+
+	results := ver.Results
+	if len(results) == 0 && ver.BestResult != nil {
+		results = []*vlib.ResultData{ver.BestResult}
+	}
+
+	var ids []int
+	best := make(map[int]*vlib.ResultData)
+	for _, v := range results {
+		if _, ok := best[v.DataSourceID]; ok {
+			continue
+		}
+		best[v.DataSourceID] = v
+		ids = append(ids, v.DataSourceID)
+	}
+	if len(dataSources) > 0 && !slices.Equal(dataSources, []int{0}) {
+		ids = dataSources
+	}
+
+	for _, id := range ids {
+		if v, ok := best[id]; ok {
+			res.Sources = append(res.Sources, newSource(v))
+		}
+	}
+	res.Disagree = disagree(res.Sources)
+	return res
+}
+
+func newSource(r *vlib.ResultData) Source {
+	res := Source{
+		DataSourceID:     r.DataSourceID,
+		DataSourceTitle:  r.DataSourceTitleShort,
+		MatchedName:      r.MatchedName,
+		TaxonomicStatus:  r.TaxonomicStatus.String(),
+		AcceptedName:     r.CurrentName,
+		AcceptedRecordID: r.CurrentRecordID,
+		canonical:        AcceptedCanonical(r),
+	}
+	return res
+}
+
+// AcceptedCanonical returns the accepted name of a result without
+// authorship, so data-sources that differ only in authors agree. It is
+// empty for data-sources without taxonomic data.
+func AcceptedCanonical(r *vlib.ResultData) string {
+	if r.TaxonomicStatus == vlib.UnknownTaxStatus {
+		return ""
+	}
+	if r.CurrentCanonicalSimple != "" {
+		return r.CurrentCanonicalSimple
+	}
+	return r.CurrentName
+}
+
+// disagree is true if sources have different accepted names.
+func disagree(srcs []Source) bool {
+	var first string
+	for _, v := range srcs {
+		if v.canonical == "" {
+			continue
+		}
+		if first == "" {
+			first = v.canonical
+			continue
+		}
+		if v.canonical != first {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package resolve_test
+
+import (
+	"testing"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/resolve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	syn := &vlib.ResultData{
+		DataSourceID:           1,
+		MatchedName:            "Felis leo",
+		TaxonomicStatus:        vlib.SynonymTaxStatus,
+		CurrentName:            "Panthera leo (Linnaeus, 1758)",
+		CurrentCanonicalSimple: "Panthera leo",
+	}
+	acc := &vlib.ResultData{
+		DataSourceID:           11,
+		MatchedName:            "Panthera leo",
+		TaxonomicStatus:        vlib.AcceptedTaxStatus,
+		CurrentName:            "Panthera leo (L.)",
+		CurrentCanonicalSimple: "Panthera leo",
+	}
+	other := &vlib.ResultData{
+		DataSourceID:           9,
+		MatchedName:            "Felis leo",
+		TaxonomicStatus:        vlib.AcceptedTaxStatus,
+		CurrentName:            "Felis leo Linnaeus, 1758",
+		CurrentCanonicalSimple: "Felis leo",
+	}
+	na := &vlib.ResultData{
+		DataSourceID:           12,
+		MatchedName:            "Felis leo",
+		TaxonomicStatus:        vlib.UnknownTaxStatus,
+		CurrentName:            "Felis leo",
+		CurrentCanonicalSimple: "Felis leo",
+	}
+	worse := &vlib.ResultData{
+		DataSourceID:           1,
+		MatchedName:            "Felis leoo",
+		TaxonomicStatus:        vlib.AcceptedTaxStatus,
+		CurrentName:            "Felis leoo",
+		CurrentCanonicalSimple: "Felis leoo",
+	}
+
+	tests := []struct {
+		msg      string
+		results  []*vlib.ResultData
+		best     *vlib.ResultData
+		sources  []int
+		ids      []int
+		disagree bool
+	}{
+		{"agree", []*vlib.ResultData{syn, acc}, nil, nil, []int{1, 11}, false},
+		{"disagree", []*vlib.ResultData{syn, other}, nil, nil, []int{1, 9}, true},
+		{"no taxonomy", []*vlib.ResultData{syn, na}, nil, nil, []int{1, 12}, false},
+		{"best of source", []*vlib.ResultData{syn, worse}, nil, nil, []int{1}, false},
+		{"order of sources", []*vlib.ResultData{syn, acc, other}, nil,
+			[]int{11, 1, 4}, []int{11, 1}, false},
+		{"all sources", []*vlib.ResultData{syn, other}, nil,
+			[]int{0}, []int{1, 9}, true},
+		{"best result", nil, acc, nil, []int{11}, false},
+		{"no match", nil, nil, []int{1}, nil, false},
+	}
+
+	for _, v := range tests {
+		ver := vlib.Name{Name: "Felis leo", Results: v.results, BestResult: v.best}
+		res := resolve.New(ver, v.sources)
+		assert.Equal(t, "Felis leo", res.Name, v.msg)
+		var ids []int
+		for _, s := range res.Sources {
+			ids = append(ids, s.DataSourceID)
+		}
+		assert.Equal(t, v.ids, ids, v.msg)
+		assert.Equal(t, v.disagree, res.Disagree, v.msg)
+	}
+
+	res := resolve.New(vlib.Name{Results: []*vlib.ResultData{syn}}, nil)
+	assert.Equal(t, "Synonym", res.Sources[0].TaxonomicStatus)
+	assert.Equal(t, "Panthera leo (Linnaeus, 1758)", res.Sources[0].AcceptedName)
+}
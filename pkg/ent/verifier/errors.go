@@ -0,0 +1,51 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrTimeout is returned when the verification service did not respond in
+// time, either because of the HTTP client timeout or because of the
+// deadline of the context.
+var ErrTimeout = errors.New("verification service timed out")
+
+// ErrDecode is returned when a response of the verification service cannot
+// be decoded.
+var ErrDecode = errors.New("cannot decode response of verification service")
+
+// ErrUpstream is returned when the verification service cannot be reached
+// or responds with an unexpected HTTP status.
+type ErrUpstream struct {
+	// Status is the HTTP status code of the response. It is 0 if
+	// there was no response, for example when connection failed.
+	Status int
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// Error implements error interface.
+func (e ErrUpstream) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("verification service is not available: %v", e.Err)
+	}
+	return fmt.Sprintf(
+		"verification service responded with status %d %s",
+		e.Status, http.StatusText(e.Status),
+	)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrUpstream) Unwrap() error {
+	return e.Err
+}
+
+// Retriable is true for errors that might go away on the next attempt:
+// connection failures, 5xx and 429 statuses.
+func (e ErrUpstream) Retriable() bool {
+	return e.Status == 0 ||
+		e.Status == http.StatusTooManyRequests ||
+		e.Status >= 500
+}
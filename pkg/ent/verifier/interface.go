@@ -15,8 +15,9 @@ import (
 type Verifier interface {
 	// Verify takes a slice of strings to verify, optional preferred data-sources
 	// and returns results of verification of the strings against known
-	// scientific names.
-	Verify(ctx context.Context, params vlib.Input) vlib.Output
+	// scientific names. Transport failures are returned as ErrTimeout,
+	// ErrUpstream or ErrDecode.
+	Verify(ctx context.Context, params vlib.Input) (vlib.Output, error)
 
 	// NameString takes a name-string or its ID, as well as query parameters.
 	// It returns results for this particular name-string.
@@ -0,0 +1,20 @@
+package verifier
+
+import "context"
+
+type retryHookKey struct{}
+
+// RetryHook is called by a Verifier before it repeats a failed request.
+// The attempt is the number of the upcoming attempt.
+type RetryHook func(attempt int, err error)
+
+// WithRetryHook returns a copy of the context that carries the hook.
+func WithRetryHook(ctx context.Context, hook RetryHook) context.Context {
+	return context.WithValue(ctx, retryHookKey{}, hook)
+}
+
+// RetryHookFrom returns the hook carried by the context, or nil.
+func RetryHookFrom(ctx context.Context) RetryHook {
+	hook, _ := ctx.Value(retryHookKey{}).(RetryHook)
+	return hook
+}
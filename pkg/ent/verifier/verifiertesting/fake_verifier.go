@@ -66,7 +66,7 @@ type FakeVerifier struct {
 		result1 search.Output
 		result2 error
 	}
-	VerifyStub        func(context.Context, verifiera.Input) verifiera.Output
+	VerifyStub        func(context.Context, verifiera.Input) (verifiera.Output, error)
 	verifyMutex       sync.RWMutex
 	verifyArgsForCall []struct {
 		arg1 context.Context
@@ -74,9 +74,11 @@ type FakeVerifier struct {
 	}
 	verifyReturns struct {
 		result1 verifiera.Output
+		result2 error
 	}
 	verifyReturnsOnCall map[int]struct {
 		result1 verifiera.Output
+		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
@@ -341,7 +343,7 @@ func (fake *FakeVerifier) SearchReturnsOnCall(i int, result1 search.Output, resu
 	}{result1, result2}
 }
 
-func (fake *FakeVerifier) Verify(arg1 context.Context, arg2 verifiera.Input) verifiera.Output {
+func (fake *FakeVerifier) Verify(arg1 context.Context, arg2 verifiera.Input) (verifiera.Output, error) {
 	fake.verifyMutex.Lock()
 	ret, specificReturn := fake.verifyReturnsOnCall[len(fake.verifyArgsForCall)]
 	fake.verifyArgsForCall = append(fake.verifyArgsForCall, struct {
@@ -356,9 +358,9 @@ func (fake *FakeVerifier) Verify(arg1 context.Context, arg2 verifiera.Input) ver
 		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeVerifier) VerifyCallCount() int {
@@ -367,7 +369,7 @@ func (fake *FakeVerifier) VerifyCallCount() int {
 	return len(fake.verifyArgsForCall)
 }
 
-func (fake *FakeVerifier) VerifyCalls(stub func(context.Context, verifiera.Input) verifiera.Output) {
+func (fake *FakeVerifier) VerifyCalls(stub func(context.Context, verifiera.Input) (verifiera.Output, error)) {
 	fake.verifyMutex.Lock()
 	defer fake.verifyMutex.Unlock()
 	fake.VerifyStub = stub
@@ -380,27 +382,30 @@ func (fake *FakeVerifier) VerifyArgsForCall(i int) (context.Context, verifiera.I
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeVerifier) VerifyReturns(result1 verifiera.Output) {
+func (fake *FakeVerifier) VerifyReturns(result1 verifiera.Output, result2 error) {
 	fake.verifyMutex.Lock()
 	defer fake.verifyMutex.Unlock()
 	fake.VerifyStub = nil
 	fake.verifyReturns = struct {
 		result1 verifiera.Output
-	}{result1}
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeVerifier) VerifyReturnsOnCall(i int, result1 verifiera.Output) {
+func (fake *FakeVerifier) VerifyReturnsOnCall(i int, result1 verifiera.Output, result2 error) {
 	fake.verifyMutex.Lock()
 	defer fake.verifyMutex.Unlock()
 	fake.VerifyStub = nil
 	if fake.verifyReturnsOnCall == nil {
 		fake.verifyReturnsOnCall = make(map[int]struct {
 			result1 verifiera.Output
+			result2 error
 		})
 	}
 	fake.verifyReturnsOnCall[i] = struct {
 		result1 verifiera.Output
-	}{result1}
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeVerifier) Invocations() map[string][][]interface{} {
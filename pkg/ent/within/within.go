@@ -0,0 +1,122 @@
+// Package within checks if results of verification belong to a higher
+// taxon, for example Aves. Fuzzy matches and homonyms often point to
+// names from unrelated groups, and results from the expected group should
+// be preferred.
+package within
+
+import (
+	"slices"
+	"strings"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+)
+
+// Status tells how the best result relates to the higher taxon.
+type Status string
+
+const (
+	// Inside means that the best result is within the taxon.
+	Inside Status = "inside"
+
+	// Promoted means that the best result was outside of the taxon, and
+	// another result from the taxon became the best one.
+	Promoted Status = "promoted"
+
+	// Outside means that the best result and all other results are
+	// outside of the taxon.
+	Outside Status = "outside"
+
+	// Unknown means that there is no match or the best result has no
+	// classification.
+	Unknown Status = "unknown"
+)
+
+// rank orders results: within the taxon, without classification, outside.
+type rank int
+
+const (
+	inside rank = iota
+	unknown
+	outside
+)
+
+func rankOf(r *vlib.ResultData, taxon string) rank {
+	if r.ClassificationPath == "" {
+		return unknown
+	}
+	for _, v := range strings.Split(r.ClassificationPath, "|") {
+		if strings.EqualFold(strings.TrimSpace(v), taxon) {
+			return inside
+		}
+	}
+	return outside
+}
+
+// Check returns the Status of the best result of a name. The best result
+// is Promoted if it is within the taxon, and a result outside of the taxon
+// has a higher score. It happens only after Apply, because the service
+// returns the result with the highest score as the best one.
+func Check(ver vlib.Name, taxon string) Status {
+	if ver.BestResult == nil {
+		return Unknown
+	}
+	switch rankOf(ver.BestResult, taxon) {
+	case inside:
+		if promoted(ver, taxon) {
+			return Promoted
+		}
+		return Inside
+	case outside:
+		return Outside
+	default:
+		return Unknown
+	}
+}
+
+func promoted(ver vlib.Name, taxon string) bool {
+	for _, rs := range [][]*vlib.ResultData{ver.BestResults, ver.Results} {
+		for _, v := range rs {
+			if v.SortScore > ver.BestResult.SortScore && rankOf(v, taxon) == outside {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply moves results from the taxon ahead of other results. Results
+// without classification go after results from the taxon, results
+// outside of the taxon go last. If the best result is not within the
+// taxon, the first result from the taxon, if any, becomes the best one.
+// Candidates come from BestResults and Results, so Results need to be
+// requested with data-sources or all matches to have more of them.
+func Apply(ver *vlib.Name, taxon string) Status {
+	if taxon == "" || ver.BestResult == nil {
+		return Unknown
+	}
+	ver.BestResults = sortResults(ver.BestResults, taxon)
+	ver.Results = sortResults(ver.Results, taxon)
+
+	st := Check(*ver, taxon)
+	if st == Inside {
+		return st
+	}
+	for _, rs := range [][]*vlib.ResultData{ver.BestResults, ver.Results} {
+		if len(rs) > 0 && rankOf(rs[0], taxon) == inside {
+			ver.BestResult = rs[0]
+			ver.MatchType = rs[0].MatchType
+			return Promoted
+		}
+	}
+	return st
+}
+
+// sortResults returns sorted copy of results. Results of repeated names
+// share slices, so they are not sorted in place.
+func sortResults(rs []*vlib.ResultData, taxon string) []*vlib.ResultData {
+	res := slices.Clone(rs)
+	slices.SortStableFunc(res, func(a, b *vlib.ResultData) int {
+		return int(rankOf(a, taxon)) - int(rankOf(b, taxon))
+	})
+	return res
+}
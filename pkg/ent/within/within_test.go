@@ -0,0 +1,124 @@
+package within_test
+
+import (
+	"testing"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/within"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	bird := &vlib.ResultData{
+		MatchedName:        "Bubo bubo",
+		ClassificationPath: "Animalia|Chordata|Aves|Strigidae|Bubo",
+		MatchType:          vlib.Fuzzy,
+	}
+	plant := &vlib.ResultData{
+		MatchedName:        "Bubu bubo",
+		ClassificationPath: "Plantae|Tracheophyta|Bubu",
+		MatchType:          vlib.Exact,
+	}
+	noPath := &vlib.ResultData{
+		MatchedName: "Bubo bubu",
+		MatchType:   vlib.Exact,
+	}
+
+	tests := []struct {
+		msg     string
+		best    *vlib.ResultData
+		results []*vlib.ResultData
+		status  within.Status
+		bestRes *vlib.ResultData
+		order   []*vlib.ResultData
+	}{
+		{"inside", bird, []*vlib.ResultData{plant, bird},
+			within.Inside, bird, []*vlib.ResultData{bird, plant}},
+		{"promoted", plant, []*vlib.ResultData{plant, noPath, bird},
+			within.Promoted, bird, []*vlib.ResultData{bird, noPath, plant}},
+		{"outside", plant, []*vlib.ResultData{plant},
+			within.Outside, plant, []*vlib.ResultData{plant}},
+		{"unknown", noPath, []*vlib.ResultData{plant, noPath},
+			within.Unknown, noPath, []*vlib.ResultData{noPath, plant}},
+		{"no match", nil, nil, within.Unknown, nil, nil},
+	}
+
+	for _, v := range tests {
+		ver := vlib.Name{
+			Name:       "Bubo bubo",
+			BestResult: v.best,
+			Results:    v.results,
+			MatchType:  vlib.Exact,
+		}
+		if v.best != nil {
+			ver.MatchType = v.best.MatchType
+		}
+		st := within.Apply(&ver, "aves")
+		assert.Equal(t, v.status, st, v.msg)
+		assert.Equal(t, v.bestRes, ver.BestResult, v.msg)
+		assert.Equal(t, v.order, ver.Results, v.msg)
+		if v.bestRes != nil {
+			assert.Equal(t, v.bestRes.MatchType, ver.MatchType, v.msg)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	bird := &vlib.ResultData{
+		MatchedName:        "Bubo bubo",
+		ClassificationPath: "Animalia|Aves|Bubo",
+		MatchType:          vlib.Exact,
+	}
+	ver := vlib.Name{BestResult: bird}
+	assert.Equal(t, within.Inside, within.Check(ver, "Aves"))
+	assert.Equal(t, within.Outside, within.Check(ver, "Insecta"))
+	assert.Equal(t, within.Outside, within.Check(ver, "Ave"))
+	assert.Equal(t, within.Unknown, within.Check(vlib.Name{}, "Aves"))
+}
+
+func TestCheckPromoted(t *testing.T) {
+	bird := &vlib.ResultData{
+		MatchedName:        "Bubo bubo",
+		ClassificationPath: "Animalia|Aves|Bubo",
+		MatchType:          vlib.Fuzzy,
+		SortScore:          0.5,
+	}
+	plant := &vlib.ResultData{
+		MatchedName:        "Bubu bubo",
+		ClassificationPath: "Plantae|Bubu",
+		MatchType:          vlib.Exact,
+		SortScore:          0.9,
+	}
+	ver := vlib.Name{
+		BestResult: plant,
+		MatchType:  vlib.Exact,
+		Results:    []*vlib.ResultData{plant, bird},
+	}
+	assert.Equal(t, within.Outside, within.Check(ver, "Aves"))
+	assert.Equal(t, within.Promoted, within.Apply(&ver, "Aves"))
+	assert.Equal(t, within.Promoted, within.Check(ver, "Aves"))
+
+	ver = vlib.Name{BestResult: bird, Results: []*vlib.ResultData{bird}}
+	assert.Equal(t, within.Inside, within.Check(ver, "Aves"))
+}
+
+func TestApplyShared(t *testing.T) {
+	bird := &vlib.ResultData{
+		MatchedName:        "Bubo bubo",
+		ClassificationPath: "Animalia|Aves|Bubo",
+		MatchType:          vlib.Fuzzy,
+	}
+	plant := &vlib.ResultData{
+		MatchedName:        "Bubu bubo",
+		ClassificationPath: "Plantae|Bubu",
+		MatchType:          vlib.Exact,
+	}
+	ver := vlib.Name{
+		BestResult: plant,
+		Results:    []*vlib.ResultData{plant, bird},
+	}
+	repeated := ver
+	assert.Equal(t, within.Promoted, within.Apply(&ver, "Aves"))
+	assert.Equal(t, []*vlib.ResultData{bird, plant}, ver.Results)
+	assert.Equal(t, []*vlib.ResultData{plant, bird}, repeated.Results)
+}
@@ -0,0 +1,17 @@
+package gnverifier
+
+import "github.com/gnames/gnverifier/pkg/ent/verifier"
+
+// Errors returned by GNverifier methods when the verification service
+// fails. Use errors.Is and errors.As to check them.
+var (
+	// ErrTimeout means the verification service did not respond in time.
+	ErrTimeout = verifier.ErrTimeout
+
+	// ErrDecode means the response of the verification service is broken.
+	ErrDecode = verifier.ErrDecode
+)
+
+// ErrUpstream means the verification service could not be reached, or
+// it responded with an unexpected HTTP status.
+type ErrUpstream = verifier.ErrUpstream
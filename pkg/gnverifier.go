@@ -3,15 +3,25 @@ package gnverifier
 import (
 	"context"
 	"errors"
+	"fmt"
+	"iter"
 	"log/slog"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/gnames/gnlib/ent/gnvers"
 	vlib "github.com/gnames/gnlib/ent/verifier"
 	"github.com/gnames/gnquery/ent/search"
+	"github.com/gnames/gnuuid"
 	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/clean"
+	"github.com/gnames/gnverifier/pkg/ent/observer"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/ent/resolve"
 	"github.com/gnames/gnverifier/pkg/ent/verifier"
+	"github.com/gnames/gnverifier/pkg/ent/within"
 )
 
 type gnverifier struct {
@@ -37,19 +47,26 @@ func (gnv gnverifier) GetVersion() gnvers.Version {
 }
 
 // DataSources returns meta-information about aggregated data-sources.
-func (gnv gnverifier) DataSources() ([]vlib.DataSource, error) {
-	return gnv.verifier.DataSources(context.Background())
+func (gnv gnverifier) DataSources(
+	ctx context.Context,
+) ([]vlib.DataSource, error) {
+	return gnv.verifier.DataSources(ctx)
 }
 
 // DataSource returns meta-information about a data-source found by its ID.
-func (gnv gnverifier) DataSource(id int) (vlib.DataSource, error) {
-	return gnv.verifier.DataSource(context.Background(), id)
+func (gnv gnverifier) DataSource(
+	ctx context.Context,
+	id int,
+) (vlib.DataSource, error) {
+	return gnv.verifier.DataSource(ctx, id)
 }
 
+// NameString returns verification data for a name-string or its ID.
 func (gnv gnverifier) NameString(
+	ctx context.Context,
 	inp vlib.NameStringInput,
 ) (vlib.NameStringOutput, error) {
-	return gnv.verifier.NameString(context.Background(), inp)
+	return gnv.verifier.NameString(ctx, inp)
 }
 
 // ChangeConfig modifies configuration.
@@ -65,38 +82,147 @@ func (gnv gnverifier) Config() config.Config {
 	return gnv.cfg
 }
 
-// VerifyOne verifies one input string and returns results
-// as a string in JSON or CSV format.
-func (gnv gnverifier) VerifyOne(name string) (vlib.Name, error) {
-	params := gnv.setParams([]string{name})
-	verif := gnv.verifier.Verify(context.Background(), params)
-	if len(verif.Names) < 1 {
+// VerifyOne verifies one input string and returns the result.
+func (gnv gnverifier) VerifyOne(
+	ctx context.Context,
+	name string,
+) (vlib.Name, error) {
+	names, err := gnv.VerifyBatch(ctx, []string{name})
+	if err != nil {
+		return vlib.Name{}, err
+	}
+	if len(names) < 1 {
 		return vlib.Name{}, errors.New("no verification results")
 	}
-	return verif.Names[0], nil
+	return names[0], nil
 }
 
 // VerifyBatch takes a list of name-strings, verifies them and returns
-// a batch of results back.
+// a batch of results back. If the context has no deadline, the
+// verification is limited by VerificationTimeout from the configuration.
+// If Cleaning is set, names are cleaned first. If Dedup is set, repeated
+// names are verified once.
 func (gnv gnverifier) VerifyBatch(
 	ctx context.Context,
 	nameStrings []string,
-) []vlib.Name {
+) ([]vlib.Name, error) {
+	nameStrings = gnv.cleanNames(nameStrings)
 	params := gnv.setParams(nameStrings)
-	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
-	defer cancel()
+	_, hasDeadline := ctx.Deadline()
+	if t := gnv.cfg.VerificationTimeout; t > 0 && !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t)
+		defer cancel()
+	}
+
+	var res []vlib.Name
+	var err error
+	if dd := newDedup(gnv.cfg.Dedup); dd != nil {
+		res, err = gnv.verifyDedup(ctx, dd, nameStrings)
+	} else {
+		var out vlib.Output
+		out, err = gnv.verifier.Verify(ctx, params)
+		res = out.Names
+	}
+	gnv.applyWithin(res)
+	gnv.applyDecisions(ctx, res)
+	return res, err
+}
+
+// applyDecisions changes results according to decisions of curators. If
+// an accepted record is not among results of a name, the name is verified
+// again with all matches from the data-source of the record.
+func (gnv gnverifier) applyDecisions(ctx context.Context, names []vlib.Name) {
+	if len(gnv.cfg.Decisions) == 0 {
+		return
+	}
+	// missing keeps indices of names by data-sources of their records.
+	missing := make(map[int][]int)
+	for i := range names {
+		d, ok := gnv.cfg.Decisions.Find(names[i].Name)
+		if !ok || names[i].Error != "" {
+			continue
+		}
+		if !d.Apply(&names[i]) {
+			missing[d.DataSourceID] = append(missing[d.DataSourceID], i)
+		}
+	}
+
+	for _, ds := range slices.Sorted(maps.Keys(missing)) {
+		idx := missing[ds]
+		strs := make([]string, len(idx))
+		for j, i := range idx {
+			strs[j] = names[i].Name
+		}
+		params := gnv.setParams(strs)
+		params.DataSources = []int{ds}
+		params.WithAllMatches = true
+		out, err := gnv.verifier.Verify(ctx, params)
+
+		for j, i := range idx {
+			d, _ := gnv.cfg.Decisions.Find(names[i].Name)
+			switch {
+			case err != nil:
+				names[i].Error = fmt.Sprintf(
+					"cannot find record %s of curation decision: %s", d, err,
+				)
+			case j < len(out.Names) && d.Apply(&out.Names[j]):
+				names[i].BestResult = out.Names[j].BestResult
+				names[i].MatchType = out.Names[j].MatchType
+			default:
+				names[i].Error = fmt.Sprintf(
+					"record %s of curation decision is not found", d,
+				)
+			}
+		}
+	}
+}
 
-	return gnv.verifier.Verify(ctx, params).Names
+// applyWithin prefers results from the Within taxon, if it is set.
+func (gnv gnverifier) applyWithin(names []vlib.Name) {
+	if gnv.cfg.Within == "" {
+		return
+	}
+	for i := range names {
+		within.Apply(&names[i], gnv.cfg.Within)
+	}
+}
+
+// Annotate pairs results with input strings and marks results changed by
+// the Within taxon or by decisions of curators.
+func (gnv gnverifier) Annotate(
+	inputs []string,
+	names []vlib.Name,
+) []output.AnnotatedName {
+	res := make([]output.AnnotatedName, len(names))
+	for i := range names {
+		res[i].Name = names[i]
+		if i < len(inputs) {
+			res[i].Input = inputs[i]
+		}
+		if gnv.cfg.Within != "" {
+			res[i].Within = within.Check(names[i], gnv.cfg.Within)
+		}
+		d, ok := gnv.cfg.Decisions.Find(names[i].Name)
+		if ok && names[i].Error == "" {
+			res[i].Decision = &d
+		}
+	}
+	return res
 }
 
 // VerifyStream receives batches of strings through the input
 // channel and sends results of verification via output
-// channel.
+// channel. If a batch fails, its names are sent with the error
+// message, and the error is returned when all batches are done.
+// If Dedup is set, repeated names of the stream are verified once.
+// If the context is canceled, remaining batches are dropped and
+// the context error is returned.
 func (gnv gnverifier) VerifyStream(
 	ctx context.Context,
 	in <-chan []string,
 	out chan []vlib.Name,
-) {
+) error {
 	var wg sync.WaitGroup
 	wg.Add(gnv.cfg.Jobs)
 
@@ -104,35 +230,319 @@ func (gnv gnverifier) VerifyStream(
 	defer cancel()
 
 	vwChan := gnv.loadNames(ctx, in)
+	prog := newProgress(gnv.cfg.Observer)
+	dd := newDedup(gnv.cfg.Dedup)
 
 	for i := 0; i < gnv.cfg.Jobs; i++ {
-		go gnv.verifyWorker(ctx, vwChan, out, &wg)
+		go gnv.verifyWorker(ctx, vwChan, out, prog, dd, &wg)
 	}
 
 	wg.Wait()
 	close(out)
+	err := ctx.Err()
+	if err == nil {
+		err = prog.err()
+	}
+	prog.done(err)
+	return err
 }
 
 func (gnv gnverifier) verifyWorker(
 	ctx context.Context,
-	in <-chan vlib.Input,
+	in <-chan batch,
 	out chan<- []vlib.Name,
+	prog *progress,
+	dd *dedup,
 	wg *sync.WaitGroup,
 ) {
 	defer wg.Done()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	for params := range in {
-		if len(params.NameStrings) == 0 {
+	for b := range in {
+		if len(b.names) == 0 {
 			continue
 		}
-		verif := gnv.verifier.Verify(ctx, params)
-		if len(verif.Names) < 1 {
+		names, err := gnv.verifyNames(ctx, b, dd)
+		if err != nil && ctx.Err() != nil {
+			continue
+		}
+		prog.add(len(b.names), err)
+		if len(names) < 1 {
 			slog.Warn("Did not get results from verifier")
 		}
-		out <- verif.Names
+		out <- names
+	}
+}
+
+// batch is a numbered batch of names from a verification stream.
+type batch struct {
+	// num is the number of the batch in the input, starting from 1.
+	num   int
+	names []string
+}
+
+// cleanNames returns names cleaned according to Cleaning. Without
+// cleaning steps names are returned as they are.
+func (gnv gnverifier) cleanNames(names []string) []string {
+	cl := clean.New(gnv.cfg.Cleaning...)
+	if !cl.Enabled() {
+		return names
+	}
+	res := make([]string, len(names))
+	for i := range names {
+		res[i] = cl.Clean(names[i])
+	}
+	return res
+}
+
+// verifyNames verifies a batch of names and reports the progress to
+// the observer. If verification fails, placeholder results are returned
+// together with the error. If dd is not nil, names seen before in the run
+// are not sent for verification again.
+func (gnv gnverifier) verifyNames(
+	ctx context.Context,
+	b batch,
+	dd *dedup,
+) ([]vlib.Name, error) {
+	obs := gnv.cfg.Observer
+	if obs != nil {
+		obs.BatchStart(observer.BatchStart{Batch: b.num, Size: len(b.names)})
+		ctx = verifier.WithRetryHook(ctx, func(attempt int, err error) {
+			obs.Retry(observer.Retry{Batch: b.num, Attempt: attempt, Err: err})
+		})
+	}
+
+	b.names = gnv.cleanNames(b.names)
+	start := time.Now()
+	var verif vlib.Output
+	var err error
+	if dd != nil {
+		verif.Names, err = gnv.verifyDedup(ctx, dd, b.names)
+	} else {
+		verif, err = gnv.verifier.Verify(ctx, gnv.setParams(b.names))
+	}
+	if obs != nil {
+		obs.BatchFinish(observer.BatchFinish{
+			Batch:    b.num,
+			Size:     len(b.names),
+			Duration: time.Since(start),
+			Err:      err,
+		})
+	}
+	if err != nil && dd == nil {
+		return failedNames(b.names, err), err
+	}
+	gnv.applyWithin(verif.Names)
+	gnv.applyDecisions(ctx, verif.Names)
+	return verif.Names, err
+}
+
+// progress collects statistics of a verification stream for the
+// observer and keeps errors of failed batches.
+type progress struct {
+	sync.Mutex
+	obs     observer.Observer
+	start   time.Time
+	batches int
+	names   int
+	errs    []error
+}
+
+func newProgress(obs observer.Observer) *progress {
+	return &progress{obs: obs, start: time.Now()}
+}
+
+// add records a processed batch.
+func (p *progress) add(size int, err error) {
+	p.Lock()
+	defer p.Unlock()
+	p.batches++
+	p.names += size
+	if err != nil {
+		p.errs = append(p.errs, err)
+	}
+}
+
+// err returns the first error, annotated by the number of failed
+// batches.
+func (p *progress) err() error {
+	p.Lock()
+	defer p.Unlock()
+	switch len(p.errs) {
+	case 0:
+		return nil
+	case 1:
+		return p.errs[0]
+	default:
+		return fmt.Errorf(
+			"verification failed for %d batches, first error: %w",
+			len(p.errs), p.errs[0],
+		)
+	}
+}
+
+// done reports the end of the stream to the observer.
+func (p *progress) done(err error) {
+	if p.obs == nil {
+		return
+	}
+	p.Lock()
+	defer p.Unlock()
+	p.obs.Done(observer.Summary{
+		Batches:       p.batches,
+		Names:         p.names,
+		FailedBatches: len(p.errs),
+		Duration:      time.Since(p.start),
+		Err:           err,
+	})
+}
+
+// failedNames creates placeholder results for names of a failed batch.
+func failedNames(names []string, err error) []vlib.Name {
+	res := make([]vlib.Name, len(names))
+	for i, name := range names {
+		res[i] = vlib.Name{
+			ID:    gnuuid.New(name).String(),
+			Name:  name,
+			Error: err.Error(),
+		}
 	}
+	return res
+}
+
+// batchResult keeps results of one batch of VerifyIter.
+type batchResult struct {
+	size  int
+	names []vlib.Name
+	err   error
+}
+
+// VerifyIter verifies names from a sequence in batches of Batch size,
+// running up to Jobs batches at a time, and yields results in the order of
+// the input. If a batch fails, its names are yielded with the Error field
+// set, together with the error. If the context is canceled, the context
+// error is yielded and the iteration ends.
+func (gnv gnverifier) VerifyIter(
+	ctx context.Context,
+	names iter.Seq[string],
+) iter.Seq2[vlib.Name, error] {
+	return func(yield func(vlib.Name, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		prog := newProgress(gnv.cfg.Observer)
+
+		// queue keeps channels of batches in the order of the input.
+		queue := make(chan chan batchResult, max(gnv.cfg.Jobs, 1))
+		go gnv.iterBatches(ctx, names, queue, &wg)
+		// names are not read and verifications are not running after
+		// the return.
+		var err error
+		defer func() {
+			cancel()
+			for range queue {
+			}
+			wg.Wait()
+			prog.done(err)
+		}()
+
+		for ch := range queue {
+			var res batchResult
+			select {
+			case <-ctx.Done():
+			case res = <-ch:
+			}
+			if err = ctx.Err(); err != nil {
+				yield(vlib.Name{}, err)
+				return
+			}
+			prog.add(res.size, res.err)
+			for _, name := range res.names {
+				if !yield(name, res.err) {
+					return
+				}
+			}
+		}
+		if err = ctx.Err(); err != nil {
+			yield(vlib.Name{}, err)
+			return
+		}
+		err = prog.err()
+	}
+}
+
+// iterBatches splits names into batches and verifies them concurrently.
+// A channel for results of every batch is sent to the queue in the order
+// of the input.
+func (gnv gnverifier) iterBatches(
+	ctx context.Context,
+	names iter.Seq[string],
+	queue chan<- chan batchResult,
+	wg *sync.WaitGroup,
+) {
+	defer close(queue)
+	batchSize := max(gnv.cfg.Batch, 1)
+	sem := make(chan struct{}, max(gnv.cfg.Jobs, 1))
+	dd := newDedup(gnv.cfg.Dedup)
+	var num int
+
+	send := func(chunk []string) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case sem <- struct{}{}:
+		}
+		ch := make(chan batchResult, 1)
+		select {
+		case <-ctx.Done():
+			return false
+		case queue <- ch:
+		}
+		num++
+		b := batch{num: num, names: chunk}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := gnv.verifyNames(ctx, b, dd)
+			ch <- batchResult{size: len(b.names), names: res, err: err}
+		}()
+		return true
+	}
+
+	chunk := make([]string, 0, batchSize)
+	for name := range names {
+		if ctx.Err() != nil {
+			return
+		}
+		chunk = append(chunk, name)
+		if len(chunk) < batchSize {
+			continue
+		}
+		if !send(chunk) {
+			return
+		}
+		chunk = make([]string, 0, batchSize)
+	}
+	if len(chunk) > 0 {
+		send(chunk)
+	}
+}
+
+// Resolve takes names, verifies them and returns their accepted names.
+func (gnv gnverifier) Resolve(
+	ctx context.Context,
+	names []string,
+) ([]resolve.Resolution, error) {
+	vers, err := gnv.VerifyBatch(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]resolve.Resolution, len(vers))
+	for i := range vers {
+		res[i] = resolve.New(vers[i], gnv.cfg.DataSources)
+	}
+	return res, nil
 }
 
 func (gnv gnverifier) Search(
@@ -146,17 +556,20 @@ func (gnv gnverifier) Search(
 func (gnv gnverifier) loadNames(
 	ctx context.Context,
 	inChan <-chan []string,
-) <-chan vlib.Input {
-	vwChan := make(chan vlib.Input)
+) <-chan batch {
+	vwChan := make(chan batch)
 	go func() {
 		defer close(vwChan)
+		var num int
 		for names := range inChan {
-
-			params := gnv.setParams(names)
+			num++
 			select {
 			case <-ctx.Done():
+				// drain the input, so senders are not blocked.
+				for range inChan {
+				}
 				return
-			case vwChan <- params:
+			case vwChan <- batch{num: num, names: names}:
 			}
 		}
 	}()
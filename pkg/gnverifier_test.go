@@ -3,17 +3,29 @@ package gnverifier_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dnaeon/go-vcr/cassette"
 	"github.com/gnames/gnfmt"
 	vlib "github.com/gnames/gnlib/ent/verifier"
+	"github.com/gnames/gnuuid"
 	gnverifier "github.com/gnames/gnverifier/pkg"
 	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/clean"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
+	"github.com/gnames/gnverifier/pkg/ent/observer"
+	"github.com/gnames/gnverifier/pkg/ent/verifier"
 	vtest "github.com/gnames/gnverifier/pkg/ent/verifier/verifiertesting"
+	"github.com/gnames/gnverifier/pkg/ent/within"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 )
 
@@ -23,12 +35,12 @@ func TestDataSources(t *testing.T) {
 	vfr.DataSourcesReturns(dss, nil)
 	cfg := config.New()
 	gnv := gnverifier.New(cfg, vfr)
-	res, err := gnv.DataSources()
+	res, err := gnv.DataSources(context.Background())
 	assert.Nil(t, err)
 	assert.Equal(t, dss, res)
 
 	vfr.DataSourcesReturns(nil, errors.New("fake error"))
-	_, err = gnv.DataSources()
+	_, err = gnv.DataSources(context.Background())
 	assert.NotNil(t, err)
 }
 
@@ -38,7 +50,7 @@ func TestDataSource(t *testing.T) {
 	vfr.DataSourceReturns(ds, nil)
 	cfg := config.New()
 	gnv := gnverifier.New(cfg, vfr)
-	res, err := gnv.DataSource(1)
+	res, err := gnv.DataSource(context.Background(), 1)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, res.ID)
 	assert.Equal(t, "Catalogue of Life", res.Title)
@@ -72,15 +84,15 @@ func TestVerifyOne(t *testing.T) {
 	cfg := config.New()
 	gnv := gnverifier.New(cfg, vfr)
 
-	vfr.VerifyReturns(verifs)
-	res, err := gnv.VerifyOne("Pomatomus saltatrix (Linnaeus, 1766)")
+	vfr.VerifyReturns(verifs, nil)
+	res, err := gnv.VerifyOne(context.Background(), "Pomatomus saltatrix (Linnaeus, 1766)")
 	assert.Nil(t, err)
 	assert.Equal(t, "Pomatomus saltatrix (Linnaeus, 1766)", res.Name)
 	assert.NotNil(t, res.BestResult)
 	assert.Equal(t, 1, vfr.VerifyCallCount())
 
-	vfr.VerifyReturns(vlib.Output{})
-	res, err = gnv.VerifyOne("something")
+	vfr.VerifyReturns(vlib.Output{}, nil)
+	res, err = gnv.VerifyOne(context.Background(), "something")
 	assert.NotNil(t, err)
 	assert.Equal(t, "", res.Name)
 }
@@ -91,15 +103,92 @@ func TestVerifyBatch(t *testing.T) {
 	cfg := config.New()
 	gnv := gnverifier.New(cfg, vfr)
 
-	vfr.VerifyReturns(verifs)
+	vfr.VerifyReturns(verifs, nil)
 	batch := []string{
 		"Pomatomus saltatrix (Linnaeus, 1766)",
 		"Bubo bubo (Linnaeus, 1782)",
 		"NotName",
 	}
-	res := gnv.VerifyBatch(context.Background(), batch)
+	res, err := gnv.VerifyBatch(context.Background(), batch)
+	assert.Nil(t, err)
 	assert.Equal(t, 3, len(res))
 	assert.Equal(t, 1, vfr.VerifyCallCount())
+
+	vfr.VerifyReturns(vlib.Output{}, gnverifier.ErrUpstream{Status: 502})
+	_, err = gnv.VerifyBatch(context.Background(), batch)
+	var ue gnverifier.ErrUpstream
+	assert.True(t, errors.As(err, &ue))
+	assert.Equal(t, 502, ue.Status)
+}
+
+func TestResolve(t *testing.T) {
+	verifs := verifications(t)
+	vfr := new(vtest.FakeVerifier)
+	cfg := config.New(config.OptDataSources([]int{1, 12}))
+	gnv := gnverifier.New(cfg, vfr)
+
+	vfr.VerifyReturns(verifs, nil)
+	batch := []string{
+		"Pomatomus saltatrix (Linnaeus, 1766)",
+		"Bubo bubo (Linnaeus, 1782)",
+		"NotName",
+	}
+	res, err := gnv.Resolve(context.Background(), batch)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(res))
+	for i := range res {
+		assert.Equal(t, verifs.Names[i].Name, res[i].Name)
+		for _, v := range res[i].Sources {
+			assert.Contains(t, []int{1, 12}, v.DataSourceID)
+		}
+	}
+	assert.NotEmpty(t, res[0].Sources)
+	assert.Empty(t, res[2].Sources)
+
+	vfr.VerifyReturns(vlib.Output{}, gnverifier.ErrTimeout)
+	_, err = gnv.Resolve(context.Background(), batch)
+	assert.ErrorIs(t, err, gnverifier.ErrTimeout)
+}
+
+func TestVerifyBatchTimeout(t *testing.T) {
+	tests := []struct {
+		msg         string
+		timeout     time.Duration
+		ctxTimeout  time.Duration
+		hasDeadline bool
+		deadline    time.Duration
+	}{
+		{"default", 20 * time.Second, 0, true, 20 * time.Second},
+		{"no timeout", 0, 0, false, 0},
+		{"context deadline", 20 * time.Second, time.Hour, true, time.Hour},
+	}
+
+	for _, v := range tests {
+		vfr := new(vtest.FakeVerifier)
+		var hasDeadline bool
+		var deadline time.Time
+		vfr.VerifyCalls(func(
+			ctx context.Context,
+			_ vlib.Input,
+		) (vlib.Output, error) {
+			deadline, hasDeadline = ctx.Deadline()
+			return vlib.Output{}, nil
+		})
+		cfg := config.New(config.OptVerificationTimeout(v.timeout))
+		gnv := gnverifier.New(cfg, vfr)
+		ctx := context.Background()
+		if v.ctxTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, v.ctxTimeout)
+			defer cancel()
+		}
+		_, _ = gnv.VerifyBatch(ctx, []string{"Bubo bubo"})
+		assert.Equal(t, v.hasDeadline, hasDeadline, v.msg)
+		if hasDeadline {
+			assert.InDelta(t, v.deadline, time.Until(deadline),
+				float64(time.Minute), v.msg)
+		}
+	}
 }
 
 func TestVerifyStream(t *testing.T) {
@@ -108,7 +197,7 @@ func TestVerifyStream(t *testing.T) {
 	cfg := config.New()
 	gnv := gnverifier.New(cfg, vfr)
 
-	vfr.VerifyReturns(verifs)
+	vfr.VerifyReturns(verifs, nil)
 	batch := []string{
 		"Pomatomus saltatrix (Linnaeus, 1766)",
 		"Bubo bubo (Linnaeus, 1782)",
@@ -138,6 +227,47 @@ func TestVerifyStream(t *testing.T) {
 	assert.Equal(t, 3, vfr.VerifyCallCount())
 }
 
+func TestVerifyStreamErrors(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(
+		_ context.Context,
+		inp vlib.Input,
+	) (vlib.Output, error) {
+		if inp.NameStrings[0] == "Bubo bubo" {
+			return vlib.Output{}, fmt.Errorf("%w: slow", gnverifier.ErrTimeout)
+		}
+		return vlib.Output{Names: []vlib.Name{{Name: inp.NameStrings[0]}}}, nil
+	})
+	gnv := gnverifier.New(config.New(config.OptJobs(2)), vfr)
+
+	chIn := make(chan []string)
+	chOut := make(chan []vlib.Name)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- gnv.VerifyStream(context.Background(), chIn, chOut)
+	}()
+	go func() {
+		for _, v := range []string{"Bubo bubo", "Pica pica", "Bubo bubo"} {
+			chIn <- []string{v}
+		}
+		close(chIn)
+	}()
+
+	var failed int
+	for res := range chOut {
+		assert.Equal(t, 1, len(res))
+		if res[0].Error != "" {
+			failed++
+			assert.Equal(t, "Bubo bubo", res[0].Name)
+			assert.NotEmpty(t, res[0].ID)
+		}
+	}
+	assert.Equal(t, 2, failed)
+	err := <-errCh
+	assert.True(t, errors.Is(err, gnverifier.ErrTimeout))
+	assert.Contains(t, err.Error(), "2 batches")
+}
+
 func dataSources(t *testing.T) []vlib.DataSource {
 	c := cassette.New("dss")
 	data, err := os.ReadFile("io/verifrest/fixtures/dss.yaml")
@@ -165,3 +295,445 @@ func verifications(t *testing.T) vlib.Output {
 	assert.Nil(t, err)
 	return res
 }
+
+func TestVerifyIter(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(
+		_ context.Context,
+		inp vlib.Input,
+	) (vlib.Output, error) {
+		// later batches finish first.
+		n, _ := strconv.Atoi(inp.NameStrings[0])
+		time.Sleep(time.Duration(20-n) * time.Millisecond)
+		if n == 6 {
+			return vlib.Output{}, gnverifier.ErrUpstream{Status: 500}
+		}
+		res := vlib.Output{Names: make([]vlib.Name, len(inp.NameStrings))}
+		for i, v := range inp.NameStrings {
+			res.Names[i] = vlib.Name{Name: v}
+		}
+		return res, nil
+	})
+	cfg := config.New(config.OptBatch(3), config.OptJobs(4))
+	gnv := gnverifier.New(cfg, vfr)
+
+	names := func(yield func(string) bool) {
+		for i := range 10 {
+			if !yield(strconv.Itoa(i)) {
+				return
+			}
+		}
+	}
+
+	var res []string
+	var failed int
+	for name, err := range gnv.VerifyIter(context.Background(), names) {
+		res = append(res, name.Name)
+		if err != nil {
+			failed++
+			assert.NotEmpty(t, name.Error)
+			var ue gnverifier.ErrUpstream
+			assert.True(t, errors.As(err, &ue))
+		}
+	}
+	assert.Equal(t,
+		[]string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, res)
+	assert.Equal(t, 3, failed)
+	assert.Equal(t, 4, vfr.VerifyCallCount())
+}
+
+func TestVerifyIterBreak(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(
+		_ context.Context,
+		inp vlib.Input,
+	) (vlib.Output, error) {
+		return vlib.Output{Names: []vlib.Name{{Name: inp.NameStrings[0]}}}, nil
+	})
+	cfg := config.New(config.OptBatch(1), config.OptJobs(2))
+	gnv := gnverifier.New(cfg, vfr)
+
+	var read int
+	names := func(yield func(string) bool) {
+		for {
+			read++
+			if !yield("Bubo bubo") {
+				return
+			}
+		}
+	}
+
+	var count int
+	for _, err := range gnv.VerifyIter(context.Background(), names) {
+		assert.Nil(t, err)
+		count++
+		if count == 5 {
+			break
+		}
+	}
+	assert.Equal(t, 5, count)
+	// reading of names stops soon after the break.
+	assert.Less(t, read, 20)
+	assert.Less(t, vfr.VerifyCallCount(), 20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var errs []error
+	for _, err := range gnv.VerifyIter(ctx, names) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], context.Canceled)
+}
+
+type recorder struct {
+	sync.Mutex
+	observer.Nop
+	started  []int
+	finished []observer.BatchFinish
+	retries  []observer.Retry
+	summary  []observer.Summary
+}
+
+func (r *recorder) BatchStart(b observer.BatchStart) {
+	r.Lock()
+	defer r.Unlock()
+	r.started = append(r.started, b.Batch)
+}
+
+func (r *recorder) BatchFinish(b observer.BatchFinish) {
+	r.Lock()
+	defer r.Unlock()
+	r.finished = append(r.finished, b)
+}
+
+func (r *recorder) Retry(rt observer.Retry) {
+	r.Lock()
+	defer r.Unlock()
+	r.retries = append(r.retries, rt)
+}
+
+func (r *recorder) Done(s observer.Summary) {
+	r.Lock()
+	defer r.Unlock()
+	r.summary = append(r.summary, s)
+}
+
+func TestObserver(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(
+		ctx context.Context,
+		inp vlib.Input,
+	) (vlib.Output, error) {
+		if inp.NameStrings[0] == "Bubo bubo" {
+			hook := verifier.RetryHookFrom(ctx)
+			require.NotNil(t, hook)
+			hook(2, gnverifier.ErrUpstream{Status: 503})
+			return vlib.Output{}, gnverifier.ErrUpstream{Status: 503}
+		}
+		res := vlib.Output{Names: make([]vlib.Name, len(inp.NameStrings))}
+		for i, v := range inp.NameStrings {
+			res.Names[i] = vlib.Name{Name: v}
+		}
+		return res, nil
+	})
+	batches := [][]string{{"Pica pica", "Aus bus"}, {"Bubo bubo"}, {"Aus"}}
+
+	rec := &recorder{}
+	cfg := config.New(config.OptObserver(rec), config.OptJobs(2))
+	gnv := gnverifier.New(cfg, vfr)
+	chIn := make(chan []string)
+	chOut := make(chan []vlib.Name)
+	go func() {
+		for _, v := range batches {
+			chIn <- v
+		}
+		close(chIn)
+	}()
+	go func() {
+		for range chOut {
+		}
+	}()
+	err := gnv.VerifyStream(context.Background(), chIn, chOut)
+	assert.Error(t, err)
+
+	slices.Sort(rec.started)
+	assert.Equal(t, []int{1, 2, 3}, rec.started)
+	require.Len(t, rec.finished, 3)
+	for _, v := range rec.finished {
+		assert.Equal(t, v.Batch == 2, v.Err != nil)
+	}
+	require.Len(t, rec.retries, 1)
+	assert.Equal(t, observer.Retry{
+		Batch: 2, Attempt: 2, Err: gnverifier.ErrUpstream{Status: 503},
+	}, rec.retries[0])
+	require.Len(t, rec.summary, 1)
+	assert.Equal(t, 3, rec.summary[0].Batches)
+	assert.Equal(t, 4, rec.summary[0].Names)
+	assert.Equal(t, 1, rec.summary[0].FailedBatches)
+	assert.Equal(t, err, rec.summary[0].Err)
+
+	// VerifyIter reports the same events.
+	rec = &recorder{}
+	gnv = gnv.ChangeConfig(config.OptObserver(rec), config.OptBatch(2))
+	for range gnv.VerifyIter(context.Background(), slices.Values(batches[0])) {
+	}
+	assert.Equal(t, []int{1}, rec.started)
+	require.Len(t, rec.summary, 1)
+	assert.Equal(t, observer.Summary{
+		Batches: 1, Names: 2, Duration: rec.summary[0].Duration,
+	}, rec.summary[0])
+}
+
+// echoVerifier returns a verifier that matches every name exactly and
+// counts names it received.
+func echoVerifier(sent *atomic.Int32) *vtest.FakeVerifier {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(
+		_ context.Context,
+		inp vlib.Input,
+	) (vlib.Output, error) {
+		sent.Add(int32(len(inp.NameStrings)))
+		res := vlib.Output{Names: make([]vlib.Name, len(inp.NameStrings))}
+		for i, v := range inp.NameStrings {
+			res.Names[i] = vlib.Name{
+				ID:        gnuuid.New(v).String(),
+				Name:      v,
+				MatchType: vlib.Exact,
+			}
+		}
+		return res, nil
+	})
+	return vfr
+}
+
+func TestVerifyBatchDedup(t *testing.T) {
+	names := []string{
+		"Bubo bubo", "Pica pica", "Bubo bubo", "bubo  Bubo", "Pica pica",
+	}
+	tests := []struct {
+		mode config.DedupMode
+		sent int32
+	}{
+		{config.DedupNone, 5},
+		{config.DedupExact, 3},
+		{config.DedupNormalized, 2},
+	}
+	for _, v := range tests {
+		var sent atomic.Int32
+		cfg := config.New(config.OptDedup(v.mode))
+		gnv := gnverifier.New(cfg, echoVerifier(&sent))
+		res, err := gnv.VerifyBatch(context.Background(), names)
+		require.NoError(t, err)
+		assert.Equal(t, v.sent, sent.Load(), v.mode.String())
+		require.Len(t, res, len(names))
+		for i := range names {
+			assert.Equal(t, names[i], res[i].Name, v.mode.String())
+			assert.Equal(t, gnuuid.New(names[i]).String(), res[i].ID)
+			assert.Equal(t, vlib.Exact, res[i].MatchType)
+		}
+	}
+}
+
+func TestVerifyCleaning(t *testing.T) {
+	var sent atomic.Int32
+	cfg := config.New(
+		config.OptCleaning(clean.Steps()),
+		config.OptDedup(config.DedupExact),
+		config.OptBatch(2),
+	)
+	gnv := gnverifier.New(cfg, echoVerifier(&sent))
+	names := []string{"<i>Bubo</i> bubo;", "Bubo bubo", "BUBO BUBO"}
+
+	res, err := gnv.VerifyBatch(context.Background(), names)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+	for i := range res {
+		assert.Equal(t, "Bubo bubo", res[i].Name)
+	}
+	assert.Equal(t, int32(1), sent.Load())
+
+	ann := gnv.Annotate(names, res)
+	require.Len(t, ann, 3)
+	assert.Equal(t, "<i>Bubo</i> bubo;", ann[0].Input)
+	assert.Equal(t, "Bubo bubo", ann[0].Name.Name)
+
+	var iterRes []string
+	for r, err := range gnv.VerifyIter(context.Background(), slices.Values(names)) {
+		require.NoError(t, err)
+		iterRes = append(iterRes, r.Name)
+	}
+	assert.Equal(t, []string{"Bubo bubo", "Bubo bubo", "Bubo bubo"}, iterRes)
+}
+
+func TestAnnotate(t *testing.T) {
+	bird := &vlib.ResultData{DataSourceID: 1, RecordID: "a",
+		ClassificationPath: "Animalia|Aves|Bubo"}
+	ds, err := curation.New([]curation.Decision{
+		{Name: "Pica pica", Reject: true, Curator: "Jane"},
+	})
+	require.NoError(t, err)
+	cfg := config.New(config.OptWithin("Aves"), config.OptDecisions(ds))
+	gnv := gnverifier.New(cfg, new(vtest.FakeVerifier))
+
+	names := []vlib.Name{
+		{Name: "Bubo bubo", BestResult: bird},
+		{Name: "Pica pica", MatchType: vlib.NoMatch},
+	}
+	res := gnv.Annotate([]string{"BUBO BUBO"}, names)
+	require.Len(t, res, 2)
+	assert.Equal(t, "BUBO BUBO", res[0].Input)
+	assert.Equal(t, within.Inside, res[0].Within)
+	assert.Nil(t, res[0].Decision)
+	assert.Equal(t, "", res[1].Input)
+	assert.Equal(t, within.Unknown, res[1].Within)
+	require.NotNil(t, res[1].Decision)
+	assert.Equal(t, "Jane", res[1].Decision.Curator)
+}
+
+func TestVerifyStreamDedup(t *testing.T) {
+	var sent atomic.Int32
+	cfg := config.New(
+		config.OptDedup(config.DedupNormalized),
+		config.OptJobs(3),
+	)
+	gnv := gnverifier.New(cfg, echoVerifier(&sent))
+
+	chIn := make(chan []string)
+	chOut := make(chan []vlib.Name)
+	go func() {
+		for range 10 {
+			chIn <- []string{"Bubo bubo", "PICA PICA", "Pica pica"}
+		}
+		close(chIn)
+	}()
+	var count int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for res := range chOut {
+			count += len(res)
+			assert.Equal(t,
+				[]string{"Bubo bubo", "PICA PICA", "Pica pica"},
+				[]string{res[0].Name, res[1].Name, res[2].Name})
+		}
+	}()
+	require.NoError(t, gnv.VerifyStream(context.Background(), chIn, chOut))
+	<-done
+	assert.Equal(t, 30, count)
+	assert.Equal(t, int32(2), sent.Load())
+
+	// VerifyIter deduplicates names too.
+	sent.Store(0)
+	list := []string{"Aus bus", "Aus bus", "aus bus", "Cus dus"}
+	var res []string
+	for name, err := range gnv.ChangeConfig(config.OptBatch(1)).
+		VerifyIter(context.Background(), slices.Values(list)) {
+		require.NoError(t, err)
+		res = append(res, name.Name)
+	}
+	assert.Equal(t, list, res)
+	assert.Equal(t, int32(2), sent.Load())
+}
+
+func TestVerifyWithin(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(
+		_ context.Context,
+		inp vlib.Input,
+	) (vlib.Output, error) {
+		var res vlib.Output
+		for _, v := range inp.NameStrings {
+			plant := &vlib.ResultData{
+				MatchedName: "Bubu bubo", MatchType: vlib.Exact,
+				ClassificationPath: "Plantae|Bubu",
+			}
+			bird := &vlib.ResultData{
+				MatchedName: "Bubo bubo", MatchType: vlib.Fuzzy,
+				ClassificationPath: "Animalia|Aves|Bubo",
+			}
+			res.Names = append(res.Names, vlib.Name{
+				Name:       v,
+				MatchType:  vlib.Exact,
+				BestResult: plant,
+				Results:    []*vlib.ResultData{plant, bird},
+			})
+		}
+		return res, nil
+	})
+	names := []string{"Bubu bubo", "Bubo bubu"}
+
+	gnv := gnverifier.New(config.New(), vfr)
+	res, err := gnv.VerifyBatch(context.Background(), names)
+	require.NoError(t, err)
+	assert.Equal(t, "Bubu bubo", res[0].BestResult.MatchedName)
+
+	gnv = gnv.ChangeConfig(config.OptWithin("Aves"))
+	res, err = gnv.VerifyBatch(context.Background(), names)
+	require.NoError(t, err)
+	for _, v := range res {
+		assert.Equal(t, "Bubo bubo", v.BestResult.MatchedName)
+		assert.Equal(t, vlib.Fuzzy, v.MatchType)
+	}
+
+	var count int
+	for v, err := range gnv.VerifyIter(context.Background(), slices.Values(names)) {
+		require.NoError(t, err)
+		assert.Equal(t, "Bubo bubo", v.BestResult.MatchedName)
+		count++
+	}
+	assert.Equal(t, 2, count)
+}
+
+func TestVerifyDecisions(t *testing.T) {
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyCalls(func(
+		_ context.Context,
+		inp vlib.Input,
+	) (vlib.Output, error) {
+		var res vlib.Output
+		for _, v := range inp.NameStrings {
+			best := &vlib.ResultData{
+				DataSourceID: 1, RecordID: "a", MatchType: vlib.Fuzzy,
+			}
+			name := vlib.Name{
+				Name:       v,
+				MatchType:  vlib.Fuzzy,
+				BestResult: best,
+				Results:    []*vlib.ResultData{best},
+			}
+			if inp.WithAllMatches && slices.Equal(inp.DataSources, []int{11}) {
+				name.Results = append(name.Results, &vlib.ResultData{
+					DataSourceID: 11, RecordID: "b", MatchType: vlib.Exact,
+				})
+			}
+			res.Names = append(res.Names, name)
+		}
+		return res, nil
+	})
+	ds, err := curation.New([]curation.Decision{
+		{Name: "Bubo bubo", DataSourceID: 1, RecordID: "a", Curator: "Jane"},
+		{Name: "Pica pica", DataSourceID: 11, RecordID: "b"},
+		{Name: "Aus bus", Reject: true},
+		{Name: "Aus cus", DataSourceID: 11, RecordID: "c"},
+	})
+	require.NoError(t, err)
+	cfg := config.New(config.OptDecisions(ds))
+	gnv := gnverifier.New(cfg, vfr)
+
+	names := []string{"Bubo bubo", "Pica pica", "Aus bus", "Aus cus", "Aus dus"}
+	res, err := gnv.VerifyBatch(context.Background(), names)
+	require.NoError(t, err)
+	require.Len(t, res, 5)
+	assert.Equal(t, "a", res[0].BestResult.RecordID)
+	assert.Equal(t, "b", res[1].BestResult.RecordID)
+	assert.Equal(t, vlib.Exact, res[1].MatchType)
+	assert.Nil(t, res[2].BestResult)
+	assert.Equal(t, vlib.NoMatch, res[2].MatchType)
+	assert.Equal(t, "a", res[3].BestResult.RecordID)
+	assert.Contains(t, res[3].Error, "11:c")
+	assert.Equal(t, "a", res[4].BestResult.RecordID)
+	assert.Empty(t, res[4].Error)
+	// one call for verification, one to find records of data-source 11.
+	assert.Equal(t, 2, vfr.VerifyCallCount())
+}
@@ -2,11 +2,14 @@ package gnverifier
 
 import (
 	"context"
+	"iter"
 
 	"github.com/gnames/gnlib/ent/gnvers"
 	vlib "github.com/gnames/gnlib/ent/verifier"
 	"github.com/gnames/gnquery/ent/search"
 	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/gnames/gnverifier/pkg/ent/resolve"
 )
 
 // GNverifier is the use-case interface of the gnverifier app. It determines
@@ -14,14 +17,40 @@ import (
 // names.
 type GNverifier interface {
 	// VerifyOne takes a name-string and returns the result of verification.
-	VerifyOne(name string) (vlib.Name, error)
+	VerifyOne(ctx context.Context, name string) (vlib.Name, error)
 
-	// VerifyBatch takes a slice of names and verifies them all at once
-	VerifyBatch(ctx context.Context, names []string) []vlib.Name
+	// VerifyBatch takes a slice of names and verifies them all at once.
+	VerifyBatch(ctx context.Context, names []string) ([]vlib.Name, error)
 
 	// VerifyStream receves batches of strings via one channel, verifies
-	// the strings and sends results to another channel.
-	VerifyStream(ctx context.Context, in <-chan []string, out chan []vlib.Name)
+	// the strings and sends results to another channel. Names of batches
+	// that failed are sent with the Error field set, the errors are
+	// returned after the output channel is closed.
+	VerifyStream(
+		ctx context.Context,
+		in <-chan []string,
+		out chan []vlib.Name,
+	) error
+
+	// VerifyIter verifies names from a sequence and returns a sequence of
+	// results in the same order. Names are verified in batches, several
+	// batches at a time. Names of a failed batch come with the error of
+	// the batch. Breaking out of the loop stops the verification.
+	VerifyIter(
+		ctx context.Context,
+		names iter.Seq[string],
+	) iter.Seq2[vlib.Name, error]
+
+	// Annotate pairs results of verification with the strings they came
+	// from, in the same order, so the original strings are kept when
+	// names are cleaned. Results also get the relation of the best result
+	// to the Within taxon and decisions of curators that changed them.
+	Annotate(inputs []string, names []vlib.Name) []output.AnnotatedName
+
+	// Resolve verifies names and returns their accepted names, one per
+	// data-source from the DataSources setting. Resolutions are flagged
+	// if data-sources disagree on the accepted name.
+	Resolve(ctx context.Context, names []string) ([]resolve.Resolution, error)
 
 	// Search provides faceted search functionality.
 	Search(ctx context.Context, srch search.Input) ([]vlib.Name, error)
@@ -34,15 +63,18 @@ type GNverifier interface {
 
 	// DataSources returns information about Data Sources harvested for
 	// verification.
-	DataSources() ([]vlib.DataSource, error)
+	DataSources(ctx context.Context) ([]vlib.DataSource, error)
 
 	// DataSource uses ID input to return meta-information about a particular
 	// data-source.
-	DataSource(id int) (vlib.DataSource, error)
+	DataSource(ctx context.Context, id int) (vlib.DataSource, error)
 
 	// NameString finds verification data by either name-string itself, or
 	// its UUID.
-	NameString(vlib.NameStringInput) (vlib.NameStringOutput, error)
+	NameString(
+		ctx context.Context,
+		inp vlib.NameStringInput,
+	) (vlib.NameStringOutput, error)
 
 	// GetVersion returns version of the gnverifier
 	GetVersion() gnvers.Version
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -16,7 +18,6 @@ import (
 	"github.com/gnames/gnfmt"
 	vlib "github.com/gnames/gnlib/ent/verifier"
 	"github.com/gnames/gnquery/ent/search"
-	"github.com/gnames/gnuuid"
 	"github.com/gnames/gnverifier/pkg/ent/verifier"
 )
 
@@ -47,67 +48,20 @@ func (vr verifrest) Search(
 	ctx context.Context,
 	inp search.Input,
 ) (search.Output, error) {
-	enc := gnfmt.GNjson{}
-	urlQ := vr.verifierURL + "search/" + url.PathEscape(inp.ToQuery())
 	var res search.Output
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, urlQ, nil)
-	if err != nil {
-		slog.Error("Cannot create request", "error", err)
-		return res, err
-	}
-	request.Header.Set("Content-Type", "application/json")
-
-	resp, err := vr.client.Do(request)
-	if err != nil {
-		slog.Error("Cannot get data-sources information", "error", err)
-		return res, err
-	}
-	defer resp.Body.Close()
-
-	var respBytes []byte
-	respBytes, err = io.ReadAll(resp.Body)
-	if err != nil {
-		slog.Error("Body reading is failing for a search", "error", err)
-		return res, err
-	}
-	err = enc.Decode(respBytes, &res)
-	if err != nil {
-		slog.Error("Cannot decode search result", "error", err)
-		return res, err
-	}
-
-	return res, nil
+	urlQ := vr.verifierURL + "search/" + url.PathEscape(inp.ToQuery())
+	err := vr.request(ctx, http.MethodGet, urlQ, nil, &res)
+	return res, err
 }
 
 // DataSources returns metadata about aggregated data-sources.
 func (vr verifrest) DataSources(
 	ctx context.Context,
 ) ([]vlib.DataSource, error) {
-	enc := gnfmt.GNjson{}
 	url := vr.verifierURL + "data_sources"
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		slog.Error("Cannot create request", "error", err)
-		return nil, err
-	}
-	request.Header.Set("Content-Type", "application/json")
-
-	resp, err := vr.client.Do(request)
-	if err != nil {
-		slog.Error("Cannot get data-sources information", "error", err)
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		slog.Error("Body reading is failing for data-sources", "error", err)
-		return nil, err
-	}
 	response := make([]vlib.DataSource, 0)
-	err = enc.Decode(respBytes, &response)
+	err := vr.request(ctx, http.MethodGet, url, nil, &response)
 	if err != nil {
-		slog.Error("Cannot decode data-sources", "error", err)
 		return nil, err
 	}
 	return response, nil
@@ -119,33 +73,9 @@ func (vr verifrest) DataSource(
 	id int,
 ) (vlib.DataSource, error) {
 	response := vlib.DataSource{}
-	enc := gnfmt.GNjson{}
 	url := fmt.Sprintf("%sdata_sources/%d", vr.verifierURL, id)
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		slog.Error("Cannot create request", "error", err)
-		return response, err
-	}
-	request.Header.Set("Content-Type", "application/json")
-
-	resp, err := vr.client.Do(request)
-	if err != nil {
-		slog.Error("Cannot get data-sources information", "error", err)
-		return response, err
-	}
-	defer resp.Body.Close()
-
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		slog.Error("Body reading is failing for data-sources", "error", err)
-		return response, err
-	}
-	err = enc.Decode(respBytes, &response)
-	if err != nil {
-		slog.Error("Cannot decode data-sources", "error", err)
-		return response, err
-	}
-	return response, nil
+	err := vr.request(ctx, http.MethodGet, url, nil, &response)
+	return response, err
 }
 
 func (vr verifrest) NameString(
@@ -153,7 +83,6 @@ func (vr verifrest) NameString(
 	input vlib.NameStringInput,
 ) (vlib.NameStringOutput, error) {
 	var res vlib.NameStringOutput
-	enc := gnfmt.GNjson{}
 	url := fmt.Sprintf("%sname_strings/%s", vr.verifierURL, input.ID)
 	var params []string
 	if len(input.DataSources) > 0 {
@@ -170,102 +99,32 @@ func (vr verifrest) NameString(
 	if len(params) > 0 {
 		url = url + "?" + strings.Join(params, "&")
 	}
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		slog.Error("Cannot create request", "error", err)
-		return res, err
-	}
-	request.Header.Set("Content-Type", "application/json")
-	resp, err := vr.client.Do(request)
-	if err != nil {
-		slog.Error("Cannot get name-string information", "error", err)
-		return res, err
-	}
-	defer resp.Body.Close()
-
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		slog.Error(
-			"Body reading is failing for a name-string response",
-			"error", err,
-		)
-		return res, err
-	}
-	err = enc.Decode(respBytes, &res)
-	if err != nil {
-		slog.Error("Cannot decode name-string response", "error", err)
-		return res, err
-	}
-	return res, nil
+	err := vr.request(ctx, http.MethodGet, url, nil, &res)
+	return res, err
 }
 
 // Verify takes names-strings and options and returns verification result.
+// Failed requests are retried if the failure might be temporary and the
+// context is not done yet.
 func (vr verifrest) Verify(
 	ctx context.Context,
 	input vlib.Input,
-) vlib.Output {
-	var attempts int
+) (vlib.Output, error) {
 	var response vlib.Output
+	if len(input.NameStrings) == 0 {
+		return response, nil
+	}
 	enc := gnfmt.GNjson{}
 	paramsData, err := enc.Encode(input)
 	if err != nil {
-		slog.Error("Cannot encode names for verification", "error", err)
+		return response, err
 	}
 
-	attempts, err = try(func(int) (bool, error) {
-		var cancel func()
-		ctx, cancel = context.WithCancel(ctx)
-		// client has Timeout, meaning cancel will propagate to the server after
-		// the set time.
-		defer cancel()
-
-		d := bytes.NewReader(paramsData)
-		namesRange := fmt.Sprintf(
-			"%s-%s",
-			input.NameStrings[0],
-			input.NameStrings[len(input.NameStrings)-1],
-		)
-
-		url := vr.verifierURL + "verifications"
-		var request *http.Request
-		var resp *http.Response
-		var respBytes []byte
-		request, err = http.NewRequestWithContext(ctx, http.MethodPost, url, d)
-		if err != nil {
-			slog.Error("Cannot create request", "error", err)
-		}
-		request.Header.Set("Content-Type", "application/json")
-
-		resp, err = vr.client.Do(request)
-		if err != nil {
-			slog.Error(
-				"Request is failing for %s",
-				"names-range", namesRange,
-				"error", err,
-			)
-			return true, err
-		}
-		defer resp.Body.Close()
-
-		respBytes, err = io.ReadAll(resp.Body)
-		if err != nil {
-			slog.Error(
-				"Body reading is failing for",
-				"names-range", namesRange,
-				"error", err,
-			)
-			return true, err
-		}
-		err = enc.Decode(respBytes, &response)
-		if err != nil {
-			slog.Error(
-				"Response decoding is failing for",
-				"names-range", namesRange,
-				"error", err,
-			)
-			return true, err
-		}
-		return false, nil
+	url := vr.verifierURL + "verifications"
+	attempts, err := try(ctx, func(int) (bool, error) {
+		response = vlib.Output{}
+		err := vr.request(ctx, http.MethodPost, url, paramsData, &response)
+		return retriable(ctx, err), err
 	})
 
 	if err != nil {
@@ -279,21 +138,81 @@ func (vr verifrest) Verify(
 			"attempts", attempts,
 			"error", err,
 		)
-		res := vlib.Output{Names: make([]vlib.Name, len(input.NameStrings))}
-		for i := range input.NameStrings {
-			name := input.NameStrings[i]
-			res.Names[i] = vlib.Name{
-				ID:    gnuuid.New(name).String(),
-				Name:  name,
-				Error: err.Error(),
-			}
-		}
-		return res
+		return vlib.Output{}, err
 	}
-	return response
+	return response, nil
 }
 
-func try(fn func(int) (bool, error)) (int, error) {
+// request sends a request to the verification service and decodes its
+// JSON response into res. Transport failures are converted to
+// verifier.ErrTimeout, verifier.ErrUpstream or verifier.ErrDecode.
+func (vr verifrest) request(
+	ctx context.Context,
+	method, url string,
+	body []byte,
+	res any,
+) error {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := vr.client.Do(request)
+	if err != nil {
+		return transportError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return transportError(ctx, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return verifier.ErrUpstream{Status: resp.StatusCode}
+	}
+
+	err = gnfmt.GNjson{}.Decode(respBytes, res)
+	if err != nil {
+		return fmt.Errorf("%w: %w", verifier.ErrDecode, err)
+	}
+	return nil
+}
+
+// transportError converts an error of the HTTP client to a typed error.
+// Cancellation by the caller is returned as is.
+func transportError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return ctx.Err()
+	}
+	var ne net.Error
+	if errors.Is(err, context.DeadlineExceeded) ||
+		(errors.As(err, &ne) && ne.Timeout()) {
+		return fmt.Errorf("%w: %w", verifier.ErrTimeout, err)
+	}
+	return verifier.ErrUpstream{Err: err}
+}
+
+// retriable decides if a failed request is worth another attempt.
+func retriable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, verifier.ErrTimeout) {
+		return true
+	}
+	var ue verifier.ErrUpstream
+	return errors.As(err, &ue) && ue.Retriable()
+}
+
+// try calls fn until it succeeds, says there is no sense to try again, or
+// the number of attempts is exhausted. The retry hook from the context is
+// called before every repeated attempt.
+func try(ctx context.Context, fn func(int) (bool, error)) (int, error) {
 	var (
 		err        error
 		tryAgain   bool
@@ -309,7 +228,14 @@ func try(fn func(int) (bool, error)) (int, error) {
 		if attempt > maxRetries {
 			return maxRetries, err
 		}
-		time.Sleep(200 * time.Millisecond)
+		if hook := verifier.RetryHookFrom(ctx); hook != nil {
+			hook(attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return attempt - 1, err
+		case <-time.After(200 * time.Millisecond):
+		}
 	}
 	return attempt, err
 }
@@ -2,12 +2,17 @@ package verifrest
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dnaeon/go-vcr/recorder"
 	vlib "github.com/gnames/gnlib/ent/verifier"
 	"github.com/gnames/gnquery"
+	"github.com/gnames/gnverifier/pkg/ent/verifier"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -145,7 +150,8 @@ func TestVerify(t *testing.T) {
 			verifierURL: urlAPI,
 			client:      client,
 		}
-		vs := verif.Verify(context.Background(), tests[i].params)
+		vs, err := verif.Verify(context.Background(), tests[i].params)
+		assert.Nil(t, err)
 		for j := range vs.Names {
 			assert.Equal(t, tests[i].matchTypes[j], vs.Names[j].MatchType)
 			if tests[i].matchCanonicals[j] != "" {
@@ -161,3 +167,76 @@ func TestVerify(t *testing.T) {
 		r.Stop()
 	}
 }
+
+func TestVerifyErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			switch r.URL.Query().Get("case") {
+			case "bad-gateway":
+				w.WriteHeader(http.StatusBadGateway)
+			case "not-found":
+				w.WriteHeader(http.StatusNotFound)
+			case "slow":
+				time.Sleep(200 * time.Millisecond)
+			default:
+				_, _ = w.Write([]byte("{not json"))
+			}
+		},
+	))
+	defer srv.Close()
+
+	inp := vlib.Input{NameStrings: []string{"Bubo bubo"}}
+	verif := &verifrest{client: srv.Client()}
+	var ue verifier.ErrUpstream
+
+	// 5xx statuses are retried.
+	var retries []int
+	ctx := verifier.WithRetryHook(context.Background(),
+		func(attempt int, err error) {
+			retries = append(retries, attempt)
+			assert.True(t, errors.As(err, &ue))
+		})
+	verif.verifierURL = srv.URL + "/?case=bad-gateway&"
+	_, err := verif.Verify(ctx, inp)
+	assert.True(t, errors.As(err, &ue))
+	assert.Equal(t, http.StatusBadGateway, ue.Status)
+	assert.Equal(t, int32(3), calls.Swap(0))
+	assert.Equal(t, []int{2, 3}, retries)
+
+	// 4xx statuses are not retried.
+	verif.verifierURL = srv.URL + "/?case=not-found&"
+	_, err = verif.DataSource(context.Background(), 1)
+	assert.True(t, errors.As(err, &ue))
+	assert.Equal(t, http.StatusNotFound, ue.Status)
+	_, err = verif.Verify(context.Background(), inp)
+	assert.True(t, errors.As(err, &ue))
+	assert.Equal(t, int32(2), calls.Swap(0))
+
+	verif.verifierURL = srv.URL + "/?case=broken&"
+	_, err = verif.Verify(context.Background(), inp)
+	assert.True(t, errors.Is(err, verifier.ErrDecode))
+	assert.Equal(t, int32(1), calls.Swap(0))
+
+	// deadline of the context is a timeout, it is not retried.
+	verif.verifierURL = srv.URL + "/?case=slow&"
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = verif.Verify(ctx, inp)
+	assert.True(t, errors.Is(err, verifier.ErrTimeout))
+	assert.Equal(t, int32(1), calls.Swap(0))
+
+	// cancellation is returned as is.
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = verif.Verify(ctx, inp)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, errors.Is(err, verifier.ErrTimeout))
+
+	// no connection.
+	srv.Close()
+	_, err = verif.DataSources(context.Background())
+	assert.True(t, errors.As(err, &ue))
+	assert.Equal(t, 0, ue.Status)
+}
@@ -0,0 +1,292 @@
+package web
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v2"
+)
+
+// apiKey describes one client of a shared GNverifier server. Keys are
+// loaded from a YAML file that contains a list of such records.
+type apiKey struct {
+	// Key is the secret a client sends with every request.
+	Key string `yaml:"key"`
+
+	// Client is a human-readable name of the client used in logs.
+	Client string `yaml:"client"`
+
+	// NamesPerDay is the maximum number of names the client can verify
+	// during one UTC day. Zero means no limit.
+	NamesPerDay int `yaml:"namesPerDay"`
+
+	// RequestsPerMinute limits the rate of requests of the client.
+	// Zero means no limit.
+	RequestsPerMinute int `yaml:"requestsPerMinute"`
+
+	// Public marks a record without a key. Its limits are shared by all
+	// requests for HTML pages without a key when the UI is public. If
+	// there is no such record, these requests have no limits.
+	Public bool `yaml:"public"`
+}
+
+// apiClient keeps usage statistics of one API key.
+type apiClient struct {
+	apiKey
+
+	// keyHash is SHA-256 hash of the key, it is used to compare keys in
+	// constant time.
+	keyHash [sha256.Size]byte
+	limiter *rate.Limiter
+
+	mu        sync.Mutex
+	day       string
+	namesUsed int
+}
+
+// keyStore holds all known API keys and their usage.
+type keyStore struct {
+	clients []*apiClient
+
+	// public limits verifications of the public UI, it can be nil.
+	public *apiClient
+	now    func() time.Time
+}
+
+// loadAPIKeys reads API keys from a YAML file.
+func loadAPIKeys(path string) (*keyStore, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read API keys file: %w", err)
+	}
+	var keys []apiKey
+	if err = yaml.Unmarshal(bs, &keys); err != nil {
+		return nil, fmt.Errorf("cannot parse API keys file %s: %w", path, err)
+	}
+	return newKeyStore(keys)
+}
+
+func newKeyStore(keys []apiKey) (*keyStore, error) {
+	res := &keyStore{
+		clients: make([]*apiClient, 0, len(keys)),
+		now:     time.Now,
+	}
+	seen := make(map[string]struct{}, len(keys))
+	for _, v := range keys {
+		if v.Public {
+			if v.Key != "" {
+				return nil, errors.New("public record cannot have an API key")
+			}
+			if res.public != nil {
+				return nil, errors.New("duplicate public record")
+			}
+			if v.Client == "" {
+				v.Client = "public"
+			}
+			res.public = newAPIClient(v)
+			continue
+		}
+		if v.Key == "" {
+			return nil, errors.New("API key cannot be empty")
+		}
+		if _, ok := seen[v.Key]; ok {
+			return nil, fmt.Errorf("duplicate API key for client '%s'", v.Client)
+		}
+		seen[v.Key] = struct{}{}
+		if v.Client == "" {
+			v.Client = "unnamed"
+		}
+		res.clients = append(res.clients, newAPIClient(v))
+	}
+	return res, nil
+}
+
+func newAPIClient(k apiKey) *apiClient {
+	res := &apiClient{apiKey: k, keyHash: sha256.Sum256([]byte(k.Key))}
+	if k.RequestsPerMinute > 0 {
+		lim := rate.Every(time.Minute / time.Duration(k.RequestsPerMinute))
+		res.limiter = rate.NewLimiter(lim, k.RequestsPerMinute)
+	}
+	return res
+}
+
+// apiKeyAuth creates middleware that requires a valid API key, enforces
+// quotas of the key and logs its usage. If WebPublicUI is true, requests
+// for HTML pages do not need a key, their verifications are limited by
+// the public record of the keys file, if it exists. Static files are
+// always public.
+func apiKeyAuth(ks *keyStore, cfg config.Config) echo.MiddlewareFunc {
+	static := cfg.WebBasePath + "/static/"
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if strings.HasPrefix(c.Request().URL.Path, static) {
+				return next(c)
+			}
+			namesNum := requestNamesNum(c, cfg.NamesNumThreshold)
+
+			key := requestKey(c)
+			if key == "" && cfg.WebPublicUI && !isAPIRequest(c) {
+				if ks.public == nil || namesNum == 0 {
+					return next(c)
+				}
+				return limit(c, ks, ks.public, namesNum, next)
+			}
+
+			cl, ok := ks.find(key)
+			if !ok {
+				return echo.NewHTTPError(
+					http.StatusUnauthorized, "valid API key is required",
+				)
+			}
+			return limit(c, ks, cl, namesNum, next)
+		}
+	}
+}
+
+// find returns the client of a key. It compares hashes of the key with
+// every known key in constant time, so the response time does not reveal
+// how much of a key is correct.
+func (ks *keyStore) find(key string) (*apiClient, bool) {
+	if key == "" {
+		return nil, false
+	}
+	hash := sha256.Sum256([]byte(key))
+	var res *apiClient
+	for _, cl := range ks.clients {
+		if subtle.ConstantTimeCompare(hash[:], cl.keyHash[:]) == 1 {
+			res = cl
+		}
+	}
+	return res, res != nil
+}
+
+// limit registers a request of a client, or rejects it if the client
+// exceeds its limits. A request that is larger than the daily quota can
+// never succeed, so it is rejected without Retry-After.
+func limit(
+	c echo.Context,
+	ks *keyStore,
+	cl *apiClient,
+	namesNum int,
+	next echo.HandlerFunc,
+) error {
+	if cl.NamesPerDay > 0 && namesNum > cl.NamesPerDay {
+		slog.Warn("API request rejected",
+			"client", cl.Client, "reason", "request exceeds daily quota",
+			"names", namesNum)
+		return echo.NewHTTPError(
+			http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("request has %d names, daily quota is %d names",
+				namesNum, cl.NamesPerDay),
+		)
+	}
+	if wait, reason := ks.allow(cl, namesNum); wait > 0 {
+		slog.Warn("API request rejected",
+			"client", cl.Client, "reason", reason, "retryAfter", wait)
+		secs := int(math.Ceil(wait.Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.Itoa(secs))
+		return echo.NewHTTPError(http.StatusTooManyRequests, reason)
+	}
+
+	slog.Info("API usage",
+		"client", cl.Client,
+		"path", c.Path(),
+		"names", namesNum,
+		"namesToday", cl.used(),
+	)
+	return next(c)
+}
+
+// allow checks rate limit and daily quota of a client and registers the
+// request. If the request is not allowed, it returns the time the client
+// should wait before retrying, and the reason of rejection.
+func (ks *keyStore) allow(cl *apiClient, namesNum int) (time.Duration, string) {
+	now := ks.now().UTC()
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	day := now.Format(time.DateOnly)
+	if cl.day != day {
+		cl.day = day
+		cl.namesUsed = 0
+	}
+
+	if cl.NamesPerDay > 0 && cl.namesUsed+namesNum > cl.NamesPerDay {
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		return tomorrow.Sub(now), "daily names quota is exceeded"
+	}
+
+	if cl.limiter != nil {
+		r := cl.limiter.ReserveN(now, 1)
+		if d := r.DelayFrom(now); d > 0 {
+			r.CancelAt(now)
+			return d, "rate limit is exceeded"
+		}
+	}
+
+	cl.namesUsed += namesNum
+	return 0, ""
+}
+
+func (cl *apiClient) used() int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.namesUsed
+}
+
+// requestKey finds API key in X-API-Key header or in Authorization header
+// with Bearer scheme. Keys in query parameters are not accepted, because
+// URLs end up in logs and browser history.
+func requestKey(c echo.Context) string {
+	req := c.Request()
+	if key := req.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	auth := req.Header.Get(echo.HeaderAuthorization)
+	if key, ok := strings.CutPrefix(auth, "Bearer "); ok {
+		return strings.TrimSpace(key)
+	}
+	return ""
+}
+
+// isAPIRequest returns true if the request asks for machine-readable
+// output instead of an HTML page.
+func isAPIRequest(c echo.Context) bool {
+	switch c.FormValue("format") {
+	case "json", "csv", "tsv":
+		return true
+	}
+	return false
+}
+
+// requestNamesNum estimates how many names a request is going to verify.
+// Small POST requests to the home page are redirected to GET without
+// verification, so only the second request counts their names.
+func requestNamesNum(c echo.Context, threshold int) int {
+	if strings.Contains(c.Path(), "/name_strings/") {
+		return 1
+	}
+	names := c.FormValue("names")
+	if strings.TrimSpace(names) == "" {
+		return 0
+	}
+	if c.Request().Method == http.MethodPost &&
+		strings.HasSuffix(c.Path(), "/") &&
+		redirectsToGET(names, c.FormValue("vernaculars"), threshold) {
+		return 0
+	}
+	return len(splitAndLimitNames(names))
+}
@@ -0,0 +1,310 @@
+package web
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+	textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+	stringer      = reflect.TypeFor[fmt.Stringer]()
+)
+
+func openAPI(gnv gnverifier.GNverifier) func(echo.Context) error {
+	return func(c echo.Context) error {
+		version := gnv.GetVersion().Version
+		spec := openAPISpec(routes(), version, gnv.Config().WebBasePath)
+		return c.JSON(http.StatusOK, spec)
+	}
+}
+
+func apiExplorer(gnv gnverifier.GNverifier) func(echo.Context) error {
+	return func(c echo.Context) error {
+		data := Data{Page: "api_explorer", Version: gnv.GetVersion().Version}
+		return c.Render(http.StatusOK, "layout", data)
+	}
+}
+
+// openAPISpec generates OpenAPI 3 specification out of documented routes.
+// Schemas of results are derived from Go types via reflection.
+func openAPISpec(rts []route, version, basePath string) map[string]any {
+	schemas := make(map[string]any)
+	paths := make(map[string]any)
+
+	for _, r := range rts {
+		if r.op == nil {
+			continue
+		}
+		path := openAPIPath(r.path)
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+		item[strings.ToLower(r.method)] = r.op.spec(schemas)
+	}
+
+	server := basePath
+	if server == "" {
+		server = "/"
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title": "GNverifier",
+			"description": "Verification of scientific names against " +
+				"biodiversity data-sources.",
+			"version": version,
+			"license": map[string]any{
+				"name": "MIT",
+				"url":  "https://github.com/gnames/gnverifier/blob/master/LICENSE",
+			},
+		},
+		"servers":    []any{map[string]any{"url": server}},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+}
+
+// openAPIPath converts Echo path parameters (":id") to OpenAPI ones ("{id}").
+func openAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i := range parts {
+		if name, ok := strings.CutPrefix(parts[i], ":"); ok {
+			parts[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func (op *operation) spec(schemas map[string]any) map[string]any {
+	res := map[string]any{
+		"operationId": op.id,
+		"summary":     op.summary,
+		"tags":        []string{op.tag},
+	}
+	if op.description != "" {
+		res["description"] = op.description
+	}
+
+	var params []any
+	props := make(map[string]any)
+	var required []string
+	for _, p := range op.params {
+		if op.form && p.in == "query" {
+			props[p.name] = p.schema()
+			if p.required {
+				required = append(required, p.name)
+			}
+			continue
+		}
+		params = append(params, p.spec())
+	}
+	if len(params) > 0 {
+		res["parameters"] = params
+	}
+	if op.form {
+		body := map[string]any{"type": "object", "properties": props}
+		if len(required) > 0 {
+			body["required"] = required
+		}
+		res["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/x-www-form-urlencoded": map[string]any{
+					"schema": body,
+				},
+			},
+		}
+	}
+
+	content := map[string]any{
+		"application/json": map[string]any{
+			"schema": schemaOf(reflect.TypeOf(op.result), schemas),
+		},
+		"text/html": map[string]any{"schema": map[string]any{"type": "string"}},
+	}
+	if op.csv {
+		str := map[string]any{"schema": map[string]any{"type": "string"}}
+		content["text/csv"] = str
+		content["text/tab-separated-values"] = str
+	}
+	responses := map[string]any{
+		"200": map[string]any{
+			"description": "Successful response in the requested format.",
+			"content":     content,
+		},
+		"401": map[string]any{"description": "API key is missing or invalid."},
+		"429": map[string]any{
+			"description": "Quota or rate limit of the API key is exceeded.",
+			"headers": map[string]any{
+				"Retry-After": map[string]any{
+					"description": "Seconds to wait before retrying.",
+					"schema":      map[string]any{"type": "integer"},
+				},
+			},
+		},
+	}
+	if op.form {
+		responses["302"] = map[string]any{
+			"description": "Small requests are redirected to GET.",
+		}
+	}
+	res["responses"] = responses
+	return res
+}
+
+func (p param) schema() map[string]any {
+	res := map[string]any{"type": p.kind}
+	if len(p.enum) > 0 {
+		res["enum"] = p.enum
+	}
+	if p.array {
+		res = map[string]any{"type": "array", "items": res}
+	}
+	return res
+}
+
+func (p param) spec() map[string]any {
+	res := map[string]any{
+		"name":        p.name,
+		"in":          p.in,
+		"description": p.description,
+		"required":    p.required,
+		"schema":      p.schema(),
+	}
+	if p.array {
+		res["explode"] = true
+	}
+	return res
+}
+
+// schemaOf creates JSON schema for a Go type. Named structs are added to
+// schemas and referenced. Integer types that marshal themselves into JSON
+// strings (like vlib.MatchTypeValue) become string enums.
+func schemaOf(t reflect.Type, schemas map[string]any) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return schemaOf(t.Elem(), schemas)
+	}
+
+	if t.Kind() != reflect.Struct &&
+		(t.Implements(jsonMarshaler) || t.Implements(textMarshaler)) {
+		res := map[string]any{"type": "string"}
+		if enum := enumOf(t); len(enum) > 0 {
+			res["enum"] = enum
+		}
+		return res
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  "array",
+			"items": schemaOf(t.Elem(), schemas),
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": schemaOf(t.Elem(), schemas),
+		}
+	case reflect.Struct:
+		return structSchema(t, schemas)
+	}
+	return map[string]any{}
+}
+
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	name := t.Name()
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if name != "" {
+		if _, ok := schemas[name]; ok {
+			return ref
+		}
+		// placeholder prevents infinite recursion on recursive types
+		schemas[name] = map[string]any{}
+	}
+
+	props := make(map[string]any)
+	var required []string
+	addFields(t, schemas, props, &required)
+
+	res := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		res["required"] = required
+	}
+	if name == "" {
+		return res
+	}
+	schemas[name] = res
+	return ref
+}
+
+// addFields adds JSON properties of struct fields to props. Fields of
+// embedded structs are promoted the same way encoding/json does it.
+func addFields(
+	t reflect.Type,
+	schemas, props map[string]any,
+	required *[]string,
+) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		fname, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && fname == "" && f.Type.Kind() == reflect.Struct {
+			addFields(f.Type, schemas, props, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if fname == "" {
+			fname = f.Name
+		}
+		props[fname] = schemaOf(f.Type, schemas)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, fname)
+		}
+	}
+}
+
+// enumOf lists string values of an integer enumeration that implements
+// fmt.Stringer. Values are collected from zero until String stops
+// returning new values, or returns "N/A" that gnames uses for unknown
+// values.
+func enumOf(t reflect.Type) []string {
+	if !t.Implements(stringer) || !t.ConvertibleTo(reflect.TypeFor[int]()) {
+		return nil
+	}
+	var res []string
+	seen := make(map[string]struct{})
+	for i := range 64 {
+		v := reflect.ValueOf(i).Convert(t).Interface().(fmt.Stringer)
+		s := v.String()
+		if _, ok := seen[s]; ok || s == "" || (s == "N/A" && i > 0) {
+			break
+		}
+		seen[s] = struct{}{}
+		res = append(res, s)
+	}
+	return res
+}
@@ -0,0 +1,338 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnames/gnfmt"
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
+	"github.com/gnames/gnverifier/pkg/ent/output"
+	"github.com/labstack/echo/v4"
+)
+
+// reviewInput is the form of the review page. Choices for reviewed names
+// are read separately, because their number depends on the list.
+type reviewInput struct {
+	Names       string `form:"names"`
+	DataSources string `form:"data_sources"`
+	Curator     string `form:"curator"`
+	Export      string `form:"export"`
+
+	// Token finds results of the review page that are kept by the server,
+	// so the export does not need to verify names again.
+	Token string `form:"token"`
+}
+
+// Review is a name that needs a decision of a curator.
+type Review struct {
+	// Index is the position of the review on the page.
+	Index int
+
+	// Name is the result of verification of the name.
+	Name vlib.Name
+
+	// Candidates are all results of the name the curator can choose from.
+	Candidates []Candidate
+}
+
+// Candidate is a result a curator can accept. Nothing is accepted by
+// default, every decision has to be made by the curator.
+type Candidate struct {
+	// Value identifies the result in the form, for example "1:12345".
+	Value string
+
+	// Best is true for the best result of the service.
+	Best bool
+
+	*vlib.ResultData
+}
+
+func reviewGET(gnv gnverifier.GNverifier) func(echo.Context) error {
+	return func(c echo.Context) error {
+		data := Data{Page: "review", Version: gnv.GetVersion().Version}
+		return c.Render(http.StatusOK, "layout", data)
+	}
+}
+
+// reviewPOST verifies a list of names and shows names that need a
+// decision: fuzzy and partial matches, names without matches and names
+// with several different candidates.
+func reviewPOST(gnv gnverifier.GNverifier) func(echo.Context) error {
+	return func(c echo.Context) error {
+		inp := new(reviewInput)
+		if err := c.Bind(inp); err != nil {
+			return err
+		}
+		if strings.TrimSpace(inp.Names) == "" {
+			return c.Redirect(http.StatusFound, gnv.Config().WebBasePath+"/review")
+		}
+
+		data := Data{
+			Page:          "review",
+			Input:         inp.Names,
+			DataSourceIDs: parseDataSources(inp.DataSources),
+			Curator:       inp.Curator,
+			Version:       gnv.GetVersion().Version,
+		}
+		gnv = gnv.ChangeConfig(config.OptDataSources(data.DataSourceIDs))
+
+		names := splitAndLimitNames(data.Input)
+		verified, err := processBatchVerification(
+			c.Request().Context(), gnv, names, "REVIEW",
+		)
+		if err != nil {
+			return verifierError(err)
+		}
+		data.Verified = annotate(gnv, names, verified)
+		data.Reviews = reviews(verified)
+		data.Token, err = savedReviews.add(data.Verified)
+		if err != nil {
+			return err
+		}
+		return c.Render(http.StatusOK, "layout", data)
+	}
+}
+
+// reviewExport returns decisions of a curator as a CSV file, that can be
+// used with the --decisions flag, or results of the whole list with the
+// decisions applied. Results of the review page are kept by the server,
+// so names are not verified again, and only choices come from the form.
+func reviewExport(gnv gnverifier.GNverifier) func(echo.Context) error {
+	return func(c echo.Context) error {
+		inp := new(reviewInput)
+		if err := c.Bind(inp); err != nil {
+			return err
+		}
+		verified, ok := savedReviews.get(inp.Token)
+		if !ok {
+			return echo.NewHTTPError(
+				http.StatusGone, "results of the review expired, review names again",
+			)
+		}
+		params, err := c.FormParams()
+		if err != nil {
+			return err
+		}
+		names := make([]vlib.Name, len(verified))
+		for i := range verified {
+			names[i] = verified[i].Name
+		}
+		rs := reviews(names)
+		reviewed := make([]string, len(rs))
+		for i := range rs {
+			reviewed[i] = rs[i].Name.Name
+		}
+		ds, err := reviewDecisions(
+			reviewed, params, inp.Curator, time.Now().Format("2006-01-02"),
+		)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		if inp.Export == "decisions" {
+			attachment(c, "decisions.csv")
+			return c.String(http.StatusOK, decisionsCSV(ds))
+		}
+
+		decisions, err := curation.New(ds)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		cols := outputColumns(gnv.Config())
+		cols.Decision = true
+		rows := make([]string, len(verified)+1)
+		rows[0] = output.AnnotatedHeader(gnfmt.CSV, cols)
+		for i, v := range verified {
+			if d, ok := decisions.Find(v.Name.Name); ok && d.Apply(&v.Name) {
+				v.Decision = &d
+			}
+			rows[i+1] = output.AnnotatedNameOutput(v, gnfmt.CSV, cols)
+		}
+		attachment(c, "verified.csv")
+		return c.String(http.StatusOK, strings.Join(rows, "\n"))
+	}
+}
+
+// savedReviews keeps results of review pages until they are exported.
+var savedReviews = newReviewCache(time.Hour, 50)
+
+// reviewCache keeps results of review pages by random tokens. Exports use
+// these results, because results sent back by a browser can be changed,
+// and verifying names again doubles the cost of a review. Results expire
+// after ttl, and the oldest results are removed when there are too many.
+type reviewCache struct {
+	sync.Mutex
+	ttl   time.Duration
+	size  int
+	items map[string]reviewItem
+}
+
+type reviewItem struct {
+	names   []output.AnnotatedName
+	created time.Time
+}
+
+func newReviewCache(ttl time.Duration, size int) *reviewCache {
+	return &reviewCache{ttl: ttl, size: size, items: make(map[string]reviewItem)}
+}
+
+// add saves results of a review and returns their token.
+func (rc *reviewCache) add(names []output.AnnotatedName) (string, error) {
+	bs := make([]byte, 16)
+	if _, err := rand.Read(bs); err != nil {
+		return "", fmt.Errorf("cannot create token of review: %w", err)
+	}
+	token := hex.EncodeToString(bs)
+
+	rc.Lock()
+	defer rc.Unlock()
+	now := time.Now()
+	var oldest string
+	for k, v := range rc.items {
+		if now.Sub(v.created) > rc.ttl {
+			delete(rc.items, k)
+			continue
+		}
+		if oldest == "" || v.created.Before(rc.items[oldest].created) {
+			oldest = k
+		}
+	}
+	if len(rc.items) >= rc.size {
+		delete(rc.items, oldest)
+	}
+	rc.items[token] = reviewItem{names: names, created: now}
+	return token, nil
+}
+
+// get returns results of a review by the token, if they did not expire.
+func (rc *reviewCache) get(token string) ([]output.AnnotatedName, bool) {
+	rc.Lock()
+	defer rc.Unlock()
+	v, ok := rc.items[token]
+	if !ok || time.Since(v.created) > rc.ttl {
+		return nil, false
+	}
+	return v.names, true
+}
+
+// reviews returns names that need a decision of a curator.
+func reviews(names []vlib.Name) []Review {
+	var res []Review
+	for _, v := range names {
+		cs := candidates(v)
+		if !needsReview(v, cs) {
+			continue
+		}
+		res = append(res, Review{Index: len(res), Name: v, Candidates: cs})
+	}
+	return res
+}
+
+// needsReview is true for fuzzy and partial matches, for names without
+// matches, and for names with candidates that point to different names.
+func needsReview(ver vlib.Name, cs []Candidate) bool {
+	if ver.Error != "" {
+		return false
+	}
+	switch ver.MatchType {
+	case vlib.Fuzzy, vlib.PartialFuzzy, vlib.PartialExact, vlib.NoMatch:
+		return true
+	}
+	current := make(map[string]struct{})
+	for _, v := range cs {
+		current[v.CurrentCanonicalSimple] = struct{}{}
+	}
+	return len(ver.BestResults) > 1 || len(current) > 1
+}
+
+// candidates collects the best result and alternatives from all results
+// of a name without repeats.
+func candidates(ver vlib.Name) []Candidate {
+	rs := append([]*vlib.ResultData{ver.BestResult}, ver.BestResults...)
+	rs = append(rs, ver.Results...)
+
+	var res []Candidate
+	seen := make(map[string]struct{})
+	for _, v := range rs {
+		if v == nil {
+			continue
+		}
+		val := fmt.Sprintf("%d:%s", v.DataSourceID, v.RecordID)
+		if _, ok := seen[val]; ok {
+			continue
+		}
+		seen[val] = struct{}{}
+		res = append(res, Candidate{
+			Value:      val,
+			Best:       len(res) == 0 && v == ver.BestResult,
+			ResultData: v,
+		})
+	}
+	return res
+}
+
+// reviewDecisions converts choices of the review form to decisions. A
+// choice of the name with index i is sent as "choice_i" and is either
+// "reject", "skip", or a data-source ID and a record ID separated by a
+// colon.
+func reviewDecisions(
+	names []string,
+	params map[string][]string,
+	curator, date string,
+) ([]curation.Decision, error) {
+	var res []curation.Decision
+	for i, name := range names {
+		var choice string
+		if vs := params["choice_"+strconv.Itoa(i)]; len(vs) > 0 {
+			choice = vs[0]
+		}
+		d := curation.Decision{Name: name, Curator: curator, Date: date}
+		switch choice {
+		case "", "skip":
+			continue
+		case "reject":
+			d.Reject = true
+		default:
+			dsStr, rec, _ := strings.Cut(choice, ":")
+			id, err := strconv.Atoi(dsStr)
+			if err != nil || rec == "" {
+				return nil, fmt.Errorf("cannot parse choice '%s' for '%s'", choice, name)
+			}
+			d.DataSourceID = id
+			d.RecordID = rec
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+// decisionsCSV formats decisions in the format read by curation.Load.
+func decisionsCSV(ds []curation.Decision) string {
+	rows := []string{
+		gnfmt.ToCSV([]string{"Name", "DataSourceId", "RecordId", "Curator", "Date"}, ','),
+	}
+	for _, v := range ds {
+		dsID, rec := strconv.Itoa(v.DataSourceID), v.RecordID
+		if v.Reject {
+			dsID, rec = "", "reject"
+		}
+		rows = append(rows, gnfmt.ToCSV([]string{v.Name, dsID, rec, v.Curator, v.Date}, ','))
+	}
+	return strings.Join(rows, "\n") + "\n"
+}
+
+func attachment(c echo.Context, file string) {
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", file),
+	)
+}
@@ -0,0 +1,206 @@
+package web
+
+import (
+	"net/http"
+
+	vlib "github.com/gnames/gnlib/ent/verifier"
+	gnverifier "github.com/gnames/gnverifier/pkg"
+	"github.com/labstack/echo/v4"
+)
+
+// route describes an endpoint of the web service. The same descriptions
+// are used to register handlers and to generate OpenAPI specification.
+type route struct {
+	method  string
+	path    string
+	handler func(gnverifier.GNverifier) func(echo.Context) error
+
+	// op documents the route in OpenAPI specification. Routes without
+	// documentation serve only HTML pages and are not included.
+	op *operation
+}
+
+// operation contains OpenAPI documentation of a route.
+type operation struct {
+	id          string
+	summary     string
+	description string
+	tag         string
+	params      []param
+
+	// form is true if parameters are sent in a form-encoded request body
+	// instead of the query.
+	form bool
+
+	// result is a value of the type returned in JSON format.
+	result any
+
+	// csv is true if the route can return CSV and TSV formats.
+	csv bool
+}
+
+// param describes a parameter of an operation.
+type param struct {
+	name        string
+	in          string
+	description string
+	kind        string
+	array       bool
+	required    bool
+	enum        []string
+}
+
+var (
+	formatParam = param{
+		name:        "format",
+		in:          "query",
+		description: "Format of the output. HTML page is returned by default.",
+		kind:        "string",
+		enum:        []string{"html", "json", "csv", "tsv"},
+	}
+	verificationParams = []param{
+		{
+			name:        "names",
+			in:          "query",
+			description: "Name-strings separated by new lines (at most 5000), or an advanced search query.",
+			kind:        "string",
+			required:    true,
+		},
+		formatParam,
+		{
+			name:        "ds",
+			in:          "query",
+			description: "IDs of data-sources to return matches from.",
+			kind:        "integer",
+			array:       true,
+		},
+		{
+			name:        "vernaculars",
+			in:          "query",
+			description: "Comma-separated 3-letter codes of languages for vernacular names.",
+			kind:        "string",
+		},
+		onParam("all_matches", "Return all matches per data-source."),
+		onParam("capitalize", "Capitalize the first letter of name-strings."),
+		onParam("species_group", "Search for species groups of names."),
+		onParam("fuzzy_uninomial", "Allow fuzzy matching of uninomials."),
+		onParam("fuzzy_relaxed", "Relax fuzzy matching rules."),
+	}
+	nameStringParams = []param{
+		{
+			name:        "id",
+			in:          "path",
+			description: "UUID of a name-string, or the name-string itself.",
+			kind:        "string",
+			required:    true,
+		},
+		formatParam,
+		{
+			name:        "data_sources",
+			in:          "query",
+			description: "Comma-separated IDs of data-sources to return matches from.",
+			kind:        "string",
+		},
+		{
+			name:        "all_matches",
+			in:          "query",
+			description: "Return all matches per data-source.",
+			kind:        "boolean",
+		},
+	}
+)
+
+func onParam(name, desc string) param {
+	return param{
+		name:        name,
+		in:          "query",
+		description: desc,
+		kind:        "string",
+		enum:        []string{"on"},
+	}
+}
+
+// routes returns all endpoints of the web service, except for static
+// files.
+func routes() []route {
+	return []route{
+		{
+			method: http.MethodGet, path: "/", handler: homeGET,
+			op: &operation{
+				id:      "verifyGET",
+				summary: "Verify name-strings",
+				description: "Verifies name-strings against biodiversity data-sources. " +
+					"If the first name is an advanced search query, a search is performed instead.",
+				tag:    "verification",
+				params: verificationParams,
+				result: []vlib.Name{},
+				csv:    true,
+			},
+		},
+		{
+			method: http.MethodPost, path: "/", handler: homePOST,
+			op: &operation{
+				id:      "verifyPOST",
+				summary: "Verify name-strings sent in a form",
+				description: "Same as GET, but parameters are sent in the form body. " +
+					"Small requests are redirected to GET.",
+				tag:    "verification",
+				params: verificationParams,
+				form:   true,
+				result: []vlib.Name{},
+				csv:    true,
+			},
+		},
+		{
+			method: http.MethodGet, path: "/data_sources", handler: dataSources,
+			op: &operation{
+				id:      "dataSources",
+				summary: "List data-sources",
+				tag:     "data-sources",
+				params:  []param{formatParam},
+				result:  []vlib.DataSource{},
+			},
+		},
+		{
+			method: http.MethodGet, path: "/data_sources/:id", handler: dataSource,
+			op: &operation{
+				id:      "dataSource",
+				summary: "Get metadata of a data-source",
+				tag:     "data-sources",
+				params: []param{
+					{
+						name:        "id",
+						in:          "path",
+						description: "ID of a data-source.",
+						kind:        "integer",
+						required:    true,
+					},
+					formatParam,
+				},
+				result: vlib.DataSource{},
+			},
+		},
+		{
+			method: http.MethodGet, path: "/name_strings/:id", handler: nameString,
+			op: &operation{
+				id:      "nameString",
+				summary: "Get verification data of a name-string",
+				tag:     "verification",
+				params:  nameStringParams,
+				result:  []vlib.Name{},
+				csv:     true,
+			},
+		},
+		{
+			method: http.MethodGet, path: "/name_strings/widget/:id",
+			handler: nameStringWidget,
+		},
+		{method: http.MethodGet, path: "/review", handler: reviewGET},
+		{method: http.MethodPost, path: "/review", handler: reviewPOST},
+		{method: http.MethodPost, path: "/review/export", handler: reviewExport},
+		{method: http.MethodGet, path: "/about", handler: about},
+		{method: http.MethodGet, path: "/api", handler: api},
+		{method: http.MethodGet, path: "/api/explorer", handler: apiExplorer},
+		{method: http.MethodGet, path: "/api/openapi.json", handler: openAPI},
+	}
+}
@@ -3,13 +3,17 @@ package web
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gnames/gnfmt"
@@ -19,6 +23,7 @@ import (
 	"github.com/gnames/gnuuid"
 	gnverifier "github.com/gnames/gnverifier/pkg"
 	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/clean"
 	"github.com/gnames/gnverifier/pkg/ent/output"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -40,17 +45,14 @@ type formInput struct {
 //go:embed static
 var static embed.FS
 
-// Run starts the GNparser web service and servies both RESTful API and
-// a website.
-func Run(gnv gnverifier.GNverifier, port int) {
-	var err error
-	e := echo.New()
-
-	e.Use(middleware.Gzip())
-
-	e.Renderer, err = NewTemplate()
+// Run starts the GNverifier web service and serves both RESTful API and
+// a website. It blocks until the server fails, or until the process receives
+// SIGINT or SIGTERM. In the latter case in-flight requests get
+// WebShutdownTimeout to finish before the server stops.
+func Run(gnv gnverifier.GNverifier, port int) error {
+	e, err := newEcho(gnv)
 	if err != nil {
-		e.Logger.Fatal(err)
+		return err
 	}
 
 	handle := slog.New(slog.NewJSONHandler(os.Stderr, nil))
@@ -58,25 +60,118 @@ func Run(gnv gnverifier.GNverifier, port int) {
 		slog.String("gnApp", "gnmatcher"),
 	))
 
-	e.GET("/", homeGET(gnv))
-	e.POST("/", homePOST(gnv))
-	e.GET("/data_sources", dataSources(gnv))
-	e.GET("/data_sources/:id", dataSource(gnv))
-	e.GET("/name_strings/:id", nameString(gnv))
-	e.GET("/name_strings/widget/:id", nameStringWidget(gnv))
-	e.GET("/about", about(gnv))
-	e.GET("/api", api(gnv))
-
-	fs := http.FileServer(http.FS(static))
-	e.GET("/static/*", echo.WrapHandler(fs))
+	cfg := gnv.Config()
+	tlsCfg, err := tlsConfig(cfg)
+	if err != nil {
+		return err
+	}
 
-	addr := fmt.Sprintf(":%d", port)
+	addr := net.JoinHostPort(cfg.WebAddress, strconv.Itoa(port))
 	s := &http.Server{
 		Addr:         addr,
-		ReadTimeout:  5 * time.Minute,
-		WriteTimeout: 5 * time.Minute,
+		ReadTimeout:  cfg.WebReadTimeout,
+		WriteTimeout: cfg.WebWriteTimeout,
+		IdleTimeout:  cfg.WebIdleTimeout,
+		TLSConfig:    tlsCfg,
+	}
+
+	ctx, stop := signal.NotifyContext(
+		context.Background(), os.Interrupt, syscall.SIGTERM,
+	)
+	defer stop()
+
+	return serve(ctx, e, s, cfg.WebShutdownTimeout)
+}
+
+// newEcho creates Echo instance with all routes of the application mounted
+// under WebBasePath from the configuration.
+func newEcho(gnv gnverifier.GNverifier) (*echo.Echo, error) {
+	var err error
+	e := echo.New()
+	cfg := gnv.Config()
+
+	if len(cfg.WebCORSOrigins) > 0 {
+		e.Pre(middleware.CORSWithConfig(corsConfig(cfg)))
+	}
+	e.Use(middleware.Gzip())
+
+	base := cfg.WebBasePath
+	e.Renderer, err = NewTemplate(base)
+	if err != nil {
+		return nil, err
+	}
+
+	g := e.Group(base)
+	if cfg.WebAPIKeysFile != "" {
+		ks, err := loadAPIKeys(cfg.WebAPIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		g.Use(apiKeyAuth(ks, cfg))
+	}
+	if base != "" {
+		g.GET("", homeGET(gnv))
+	}
+	for _, r := range routes() {
+		g.Add(r.method, r.path, r.handler(gnv))
+	}
+
+	fs := http.StripPrefix(base, http.FileServer(http.FS(static)))
+	g.GET("/static/*", echo.WrapHandler(fs))
+
+	return e, nil
+}
+
+// corsConfig creates CORS settings from the configuration. The preflight
+// requests are answered before API keys are checked, because browsers do
+// not send credentials with them.
+func corsConfig(cfg config.Config) middleware.CORSConfig {
+	res := middleware.CORSConfig{
+		AllowOrigins:  cfg.WebCORSOrigins,
+		AllowMethods:  cfg.WebCORSMethods,
+		AllowHeaders:  cfg.WebCORSHeaders,
+		ExposeHeaders: []string{"Retry-After"},
+		MaxAge:        3600,
+	}
+	if len(res.AllowMethods) == 0 {
+		res.AllowMethods = []string{
+			http.MethodGet, http.MethodHead, http.MethodPost,
+		}
 	}
-	e.Logger.Fatal(e.StartServer(s))
+	return res
+}
+
+// serve starts the server and waits until either the server stops on its
+// own, or the context is canceled. After cancellation the server stops
+// accepting new connections and waits for active requests to finish for
+// up to shutdownTimeout.
+func serve(
+	ctx context.Context,
+	e *echo.Echo,
+	s *http.Server,
+	shutdownTimeout time.Duration,
+) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- e.StartServer(s)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down web server", "timeout", shutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		return fmt.Errorf("cannot shut down web server gracefully: %w", err)
+	}
+	return nil
 }
 
 type Data struct {
@@ -86,9 +181,15 @@ type Data struct {
 	DataSourceIDs []int
 	Vernaculars   []string
 	AllMatches    bool
-	Verified      []vlib.Name
+	Verified      []output.AnnotatedName
+	Columns       output.Columns
 	DataSources   []vlib.DataSource
 	DataSource    vlib.DataSource
+	Presets       []config.Preset
+	Within        string
+	Reviews       []Review
+	Token         string
+	Curator       string
 	Version       string
 }
 
@@ -144,27 +245,14 @@ func getNameString(
 ) (Data, error) {
 	var res Data
 	id, _ := url.QueryUnescape(c.Param("id"))
-	var ds []int
-	var allMatches bool
-	dsStr := c.QueryParam("data_sources")
-	if dsStr != "" {
-		dss := strings.Split(dsStr, ",")
-		for i := range dss {
-			num, err := strconv.Atoi(dss[i])
-			if err == nil {
-				ds = append(ds, num)
-			}
-		}
-	}
-	allMatches = c.QueryParam("all_matches") == "true"
 	inp := vlib.NameStringInput{
 		ID:             id,
-		DataSources:    ds,
-		WithAllMatches: allMatches,
+		DataSources:    parseDataSources(c.QueryParam("data_sources")),
+		WithAllMatches: c.QueryParam("all_matches") == "true",
 	}
-	out, err := gnv.NameString(inp)
+	out, err := gnv.NameString(c.Request().Context(), inp)
 	if err != nil {
-		return res, err
+		return res, verifierError(err)
 	}
 	var names []vlib.Name
 	if out.Name != nil {
@@ -177,7 +265,7 @@ func getNameString(
 		DataSourceIDs: inp.DataSources,
 		AllMatches:    inp.WithAllMatches,
 		Page:          "home",
-		Verified:      names,
+		Verified:      plainNames(names),
 		Version:       gnv.GetVersion().Version,
 	}
 	format := c.QueryParam("format")
@@ -188,13 +276,32 @@ func getNameString(
 	return res, nil
 }
 
+// parseDataSources converts comma-separated IDs of data-sources to
+// integers, skipping values that are not numbers.
+func parseDataSources(s string) []int {
+	var res []int
+	if s == "" {
+		return res
+	}
+	for v := range strings.SplitSeq(s, ",") {
+		num, err := strconv.Atoi(strings.TrimSpace(v))
+		if err == nil {
+			res = append(res, num)
+		}
+	}
+	return res
+}
+
 func dataSources(gnv gnverifier.GNverifier) func(echo.Context) error {
 	return func(c echo.Context) error {
 		var err error
 		data := Data{Page: "data_sources", Version: gnv.GetVersion().Version}
-		data.DataSources, err = gnv.DataSources()
+		data.DataSources, err = gnv.DataSources(c.Request().Context())
 		if err != nil {
-			return err
+			return verifierError(err)
+		}
+		if c.QueryParam("format") == "json" {
+			return c.JSON(http.StatusOK, data.DataSources)
 		}
 		return c.Render(http.StatusOK, "layout", data)
 	}
@@ -209,17 +316,25 @@ func dataSource(gnv gnverifier.GNverifier) func(echo.Context) error {
 		if err != nil {
 			return err
 		}
-		data.DataSource, err = gnv.DataSource(id)
+		data.DataSource, err = gnv.DataSource(c.Request().Context(), id)
 		if err != nil {
 			return fmt.Errorf("cannot find DataSource for id '%s'", idStr)
 		}
+		if c.QueryParam("format") == "json" {
+			return c.JSON(http.StatusOK, data.DataSource)
+		}
 		return c.Render(http.StatusOK, "layout", data)
 	}
 }
 
 func homeGET(gnv gnverifier.GNverifier) func(echo.Context) error {
 	return func(c echo.Context) error {
-		data := Data{Page: "home", Format: "html", Version: gnv.GetVersion().Version}
+		data := Data{
+			Page:    "home",
+			Format:  "html",
+			Presets: gnv.Config().WebPresets,
+			Version: gnv.GetVersion().Version,
+		}
 
 		inp := new(formInput)
 		err := c.Bind(inp)
@@ -246,25 +361,30 @@ func homePOST(gnv gnverifier.GNverifier) func(echo.Context) error {
 		}
 
 		if strings.TrimSpace(inp.Names) == "" {
-			return c.Redirect(http.StatusFound, "")
-		}
-
-		split := strings.Split(inp.Names, "\n")
-		if len(split) > 5_000 {
-			split = split[:5_000]
-		}
-		if inp.Vernaculars != "" && len(split) > 50 {
-			split = split[:50]
+			return c.Redirect(http.StatusFound, gnv.Config().WebBasePath+"/")
 		}
 
-		if len(split) < gnv.Config().NamesNumThreshold {
-			return redirectToHomeGET(c, inp)
+		if redirectsToGET(inp.Names, inp.Vernaculars, gnv.Config().NamesNumThreshold) {
+			return redirectToHomeGET(c, gnv.Config().WebBasePath, inp)
 		}
 
 		return verificationResults(c, gnv, inp, data, "POST")
 	}
 }
 
+// redirectsToGET is true if a POST request is small enough to be
+// redirected to GET, so its results can be shared by a link.
+func redirectsToGET(names, vernaculars string, threshold int) bool {
+	split := strings.Split(names, "\n")
+	if len(split) > 5_000 {
+		split = split[:5_000]
+	}
+	if vernaculars != "" && len(split) > 50 {
+		split = split[:50]
+	}
+	return len(split) < threshold
+}
+
 func getPreferredSources(ds []string) []int {
 	var res []int
 	if len(ds) == 0 {
@@ -281,7 +401,7 @@ func getPreferredSources(ds []string) []int {
 	return res
 }
 
-func redirectToHomeGET(c echo.Context, inp *formInput) error {
+func redirectToHomeGET(c echo.Context, base string, inp *formInput) error {
 	caps := inp.Capitalize == "on"
 	spGr := inp.SpeciesGroup == "on"
 	fuzzyRel := inp.FuzzyRelaxed == "on"
@@ -311,7 +431,7 @@ func redirectToHomeGET(c echo.Context, inp *formInput) error {
 	for i := range inp.DataSources {
 		q.Add("ds", strconv.Itoa(inp.DataSources[i]))
 	}
-	url := fmt.Sprintf("/?%s", q.Encode())
+	url := fmt.Sprintf("%s/?%s", base, q.Encode())
 	return c.Redirect(http.StatusFound, url)
 }
 
@@ -347,9 +467,16 @@ func verificationResults(
 
 		// Handle search query vs. batch verification
 		if len(names) > 0 && search.IsQuery(names[0]) {
-			data.Verified = processSearchQuery(c.Request().Context(), gnv, names[0], method)
+			verified := processSearchQuery(c.Request().Context(), gnv, names[0], method)
+			data.Verified = plainNames(verified)
 		} else {
-			data.Verified = processBatchVerification(c.Request().Context(), gnv, names, method)
+			verified, err := processBatchVerification(c.Request().Context(), gnv, names, method)
+			if err != nil {
+				return verifierError(err)
+			}
+			data.Verified = annotate(gnv, names, verified)
+			data.Columns = outputColumns(gnv.Config())
+			data.Within = gnv.Config().Within
 		}
 	}
 
@@ -462,12 +589,15 @@ func processBatchVerification(
 	gnv gnverifier.GNverifier,
 	names []string,
 	method string,
-) []vlib.Name {
+) ([]vlib.Name, error) {
 	if len(names) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	verified := gnv.VerifyBatch(ctx, names)
+	verified, err := gnv.VerifyBatch(ctx, names)
+	if err != nil {
+		return nil, err
+	}
 
 	slog.Info(
 		"Verification",
@@ -476,7 +606,21 @@ func processBatchVerification(
 		"method", method,
 	)
 
-	return verified
+	return verified, nil
+}
+
+// verifierError converts errors of the verification service to HTTP
+// errors: timeouts become 504, other upstream failures become 502.
+func verifierError(err error) error {
+	var ue gnverifier.ErrUpstream
+	switch {
+	case errors.Is(err, gnverifier.ErrTimeout):
+		return echo.NewHTTPError(http.StatusGatewayTimeout, err.Error())
+	case errors.As(err, &ue), errors.Is(err, gnverifier.ErrDecode):
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	default:
+		return err
+	}
 }
 
 // renderResults returns verification results in the requested format.
@@ -497,9 +641,45 @@ func renderResults(c echo.Context, data Data) error {
 
 func formatRows(data Data, f gnfmt.Format) []string {
 	res := make([]string, len(data.Verified)+1)
-	res[0] = output.CSVHeader(f)
+	res[0] = output.AnnotatedHeader(f, data.Columns)
 	for i, v := range data.Verified {
-		res[i+1] = output.NameOutput(v, f)
+		res[i+1] = output.AnnotatedNameOutput(v, f, data.Columns)
+	}
+	return res
+}
+
+// outputColumns returns optional columns of CSV and TSV output, the same
+// as in the output of the command line.
+func outputColumns(cfg config.Config) output.Columns {
+	return output.Columns{
+		Input:    clean.New(cfg.Cleaning...).Enabled(),
+		Within:   cfg.Within != "",
+		Decision: len(cfg.Decisions) > 0,
+	}
+}
+
+// annotate pairs results of verification with input names. The original
+// strings are kept only if names are cleaned.
+func annotate(
+	gnv gnverifier.GNverifier,
+	inputs []string,
+	names []vlib.Name,
+) []output.AnnotatedName {
+	res := gnv.Annotate(inputs, names)
+	if !outputColumns(gnv.Config()).Input {
+		for i := range res {
+			res[i].Input = ""
+		}
+	}
+	return res
+}
+
+// plainNames wraps results that are not changed after verification, for
+// example results of a search.
+func plainNames(names []vlib.Name) []output.AnnotatedName {
+	res := make([]output.AnnotatedName, len(names))
+	for i := range names {
+		res[i].Name = names[i]
 	}
 	return res
 }
@@ -1,21 +1,31 @@
 package web
 
 import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dnaeon/go-vcr/cassette"
 	"github.com/gnames/gnfmt"
 	vlib "github.com/gnames/gnlib/ent/verifier"
 	gnverifier "github.com/gnames/gnverifier/pkg"
 	"github.com/gnames/gnverifier/pkg/config"
+	"github.com/gnames/gnverifier/pkg/ent/clean"
+	"github.com/gnames/gnverifier/pkg/ent/curation"
 	vtest "github.com/gnames/gnverifier/pkg/ent/verifier/verifiertesting"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 )
 
@@ -24,7 +34,7 @@ func handlerGET(path string, t *testing.T) (echo.Context, *httptest.ResponseReco
 	req := httptest.NewRequest(http.MethodGet, path, nil)
 	rec := httptest.NewRecorder()
 	e := echo.New()
-	e.Renderer, err = NewTemplate()
+	e.Renderer, err = NewTemplate("")
 	assert.Nil(t, err)
 	c := e.NewContext(req, rec)
 	return c, rec
@@ -36,7 +46,7 @@ func TestAbout(t *testing.T) {
 	verifs := verifications(t)
 	cfg := config.New()
 	vfr := new(vtest.FakeVerifier)
-	vfr.VerifyReturns(verifs)
+	vfr.VerifyReturns(verifs, nil)
 	gnv := gnverifier.New(cfg, vfr)
 
 	assert.Nil(t, about(gnv)(c))
@@ -50,7 +60,7 @@ func TestAPI(t *testing.T) {
 	verifs := verifications(t)
 	cfg := config.New()
 	vfr := new(vtest.FakeVerifier)
-	vfr.VerifyReturns(verifs)
+	vfr.VerifyReturns(verifs, nil)
 	gnv := gnverifier.New(cfg, vfr)
 
 	assert.Nil(t, api(gnv)(c))
@@ -64,13 +74,34 @@ func TestHomeGET(t *testing.T) {
 	verifs := verifications(t)
 	cfg := config.New()
 	vfr := new(vtest.FakeVerifier)
-	vfr.VerifyReturns(verifs)
+	vfr.VerifyReturns(verifs, nil)
 	gnv := gnverifier.New(cfg, vfr)
 
 	assert.Nil(t, homeGET(gnv)(c))
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Contains(t, rec.Body.String(), "Global Names Verifier")
 	assert.Contains(t, rec.Body.String(), "Advanced Options")
+	assert.NotContains(t, rec.Body.String(), "id='preset'")
+}
+
+func TestHomePresets(t *testing.T) {
+	c, rec := handlerGET("/", t)
+
+	cfg := config.New(config.OptWebPresets([]config.Preset{
+		{Name: "marine", DataSources: []int{9, 1}, WithRelaxedFuzzyMatch: true},
+		{Name: "botany", DataSources: []int{196, 167}, WithSpeciesGroup: true},
+	}))
+	gnv := gnverifier.New(cfg, new(vtest.FakeVerifier))
+
+	assert.Nil(t, homeGET(gnv)(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "id='preset'")
+	assert.Contains(t, body, "<option value='marine'")
+	assert.Contains(t, body, "data-ds='9,1'")
+	assert.Contains(t, body, "data-fuzzy_relaxed='true'")
+	assert.Contains(t, body, "data-ds='196,167'")
+	assert.Contains(t, body, "data-species_group='true'")
 }
 
 func TestHomePOSTOnly(t *testing.T) {
@@ -88,13 +119,13 @@ func TestHomePOSTOnly(t *testing.T) {
 	rec := httptest.NewRecorder()
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
 	e := echo.New()
-	e.Renderer, err = NewTemplate()
+	e.Renderer, err = NewTemplate("")
 	assert.Nil(t, err)
 	c := e.NewContext(req, rec)
 
 	cfg := config.New(config.OptNamesNumThreshold(2))
 	vfr := new(vtest.FakeVerifier)
-	vfr.VerifyReturns(verifs)
+	vfr.VerifyReturns(verifs, nil)
 	gnv := gnverifier.New(cfg, vfr)
 	err = homePOST(gnv)(c)
 	assert.Nil(t, err)
@@ -102,6 +133,144 @@ func TestHomePOSTOnly(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), "Bubo (genus)")
 }
 
+func TestHomeCleaning(t *testing.T) {
+	f := make(url.Values)
+	f.Set("names", "Bubo bubo;\nPomatomus saltator\nNotName")
+	f.Set("format", "csv")
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/",
+		strings.NewReader(f.Encode()),
+	)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	cfg := config.New(
+		config.OptNamesNumThreshold(2),
+		config.OptCleaning([]clean.Step{clean.Punctuation}),
+	)
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyReturns(verifications(t), nil)
+	gnv := gnverifier.New(cfg, vfr)
+	require.Nil(t, homePOST(gnv)(c))
+	_, inp := vfr.VerifyArgsForCall(0)
+	assert.Equal(t, []string{"Bubo bubo", "Pomatomus saltator", "NotName"},
+		inp.NameStrings)
+	rows := strings.Split(rec.Body.String(), "\n")
+	assert.True(t, strings.HasPrefix(rows[0], "InputName,Kind,"))
+	assert.True(t, strings.HasPrefix(rows[1], "Bubo bubo;,"))
+}
+
+func TestHomeDecisions(t *testing.T) {
+	ds, err := curation.New([]curation.Decision{
+		{Name: "NotAName", Reject: true, Curator: "Jane", Date: "2026-01-01"},
+	})
+	require.Nil(t, err)
+	cfg := config.New(
+		config.OptNamesNumThreshold(2),
+		config.OptDecisions(ds),
+	)
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyReturns(verifications(t), nil)
+	gnv := gnverifier.New(cfg, vfr)
+
+	post := func(format string) string {
+		f := make(url.Values)
+		f.Set("names", "Bubo bubo\nPomatomus saltator\nNotAName")
+		f.Set("format", format)
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/",
+			strings.NewReader(f.Encode()),
+		)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		e := echo.New()
+		e.Renderer, err = NewTemplate("")
+		require.Nil(t, err)
+		require.Nil(t, homePOST(gnv)(e.NewContext(req, rec)))
+		return rec.Body.String()
+	}
+
+	rows := strings.Split(post("csv"), "\n")
+	assert.True(t, strings.HasSuffix(rows[0], ",Decision,Curator,DecisionDate"))
+	assert.True(t, strings.HasSuffix(rows[len(rows)-1], ",reject,Jane,2026-01-01"))
+	assert.Contains(t, post("json"), `"curator":"Jane"`)
+	assert.Contains(t, post("html"), "Curation decision: reject by Jane on 2026-01-01")
+}
+
+func TestHomeWithin(t *testing.T) {
+	cfg := config.New(
+		config.OptNamesNumThreshold(2),
+		config.OptWithin("Aves"),
+	)
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyReturns(verifications(t), nil)
+	gnv := gnverifier.New(cfg, vfr)
+
+	post := func(format string) string {
+		f := make(url.Values)
+		f.Set("names", "Bubo bubo\nPomatomus saltator\nNotAName")
+		f.Set("format", format)
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/",
+			strings.NewReader(f.Encode()),
+		)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		e := echo.New()
+		var err error
+		e.Renderer, err = NewTemplate("")
+		require.Nil(t, err)
+		require.Nil(t, homePOST(gnv)(e.NewContext(req, rec)))
+		return rec.Body.String()
+	}
+
+	rows := strings.Split(post("csv"), "\n")
+	assert.True(t, strings.HasSuffix(rows[0], ",Error,Within"))
+	assert.True(t, strings.HasSuffix(rows[1], ",outside"))
+	assert.Contains(t, post("json"), `"within":"inside"`)
+	assert.Contains(t, post("html"), "Within Aves: inside")
+}
+
+func TestHomePOSTErrors(t *testing.T) {
+	tests := []struct {
+		msg    string
+		err    error
+		status int
+	}{
+		{"timeout", fmt.Errorf("%w: slow", gnverifier.ErrTimeout),
+			http.StatusGatewayTimeout},
+		{"upstream", gnverifier.ErrUpstream{Status: 500},
+			http.StatusBadGateway},
+		{"decode", gnverifier.ErrDecode, http.StatusBadGateway},
+	}
+
+	for _, v := range tests {
+		f := make(url.Values)
+		f.Set("names", "Bubo bubo\nPomatomus saltator\nNotName")
+		f.Set("format", "json")
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/",
+			strings.NewReader(f.Encode()),
+		)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		vfr := new(vtest.FakeVerifier)
+		vfr.VerifyReturns(vlib.Output{}, v.err)
+		cfg := config.New(config.OptNamesNumThreshold(2))
+		gnv := gnverifier.New(cfg, vfr)
+		err := homePOST(gnv)(c)
+		var he *echo.HTTPError
+		require.True(t, errors.As(err, &he), v.msg)
+		assert.Equal(t, v.status, he.Code, v.msg)
+	}
+}
+
 func TestHomePostGet(t *testing.T) {
 	var err error
 	verifs := verifications(t)
@@ -117,13 +286,13 @@ func TestHomePostGet(t *testing.T) {
 	rec := httptest.NewRecorder()
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
 	e := echo.New()
-	e.Renderer, err = NewTemplate()
+	e.Renderer, err = NewTemplate("")
 	assert.Nil(t, err)
 	c := e.NewContext(req, rec)
 
 	cfg := config.New(config.OptNamesNumThreshold(20))
 	vfr := new(vtest.FakeVerifier)
-	vfr.VerifyReturns(verifs)
+	vfr.VerifyReturns(verifs, nil)
 	gnv := gnverifier.New(cfg, vfr)
 	assert.Nil(t, homePOST(gnv)(c))
 	// redirect to GET
@@ -131,6 +300,472 @@ func TestHomePostGet(t *testing.T) {
 	assert.NotContains(t, rec.Body.String(), "Bubo (genus)")
 }
 
+func TestReview(t *testing.T) {
+	var err error
+	f := make(url.Values)
+	f.Set("names", "Bubo bubo\nPomatomus saltator\nNotAName")
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/review",
+		strings.NewReader(f.Encode()),
+	)
+	rec := httptest.NewRecorder()
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	e := echo.New()
+	e.Renderer, err = NewTemplate("")
+	assert.Nil(t, err)
+	c := e.NewContext(req, rec)
+
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyReturns(verifications(t), nil)
+	gnv := gnverifier.New(config.New(), vfr)
+	assert.Nil(t, reviewPOST(gnv)(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "<h4>NotAName</h4>")
+	assert.Contains(t, body, "name='choice_0' value='reject'")
+	assert.Contains(t, body, "action='/review/export'")
+	assert.Contains(t, body, "name='token' value='")
+	assert.Equal(t,
+		strings.Count(body, "value='skip'"),
+		strings.Count(body, "checked='checked'"),
+	)
+}
+
+func TestReviews(t *testing.T) {
+	r1 := &vlib.ResultData{DataSourceID: 1, RecordID: "a",
+		CurrentCanonicalSimple: "Bubo bubo"}
+	r2 := &vlib.ResultData{DataSourceID: 11, RecordID: "b",
+		CurrentCanonicalSimple: "Bubo bubo"}
+	r3 := &vlib.ResultData{DataSourceID: 12, RecordID: "c",
+		CurrentCanonicalSimple: "Bubo sibiricus"}
+	names := []vlib.Name{
+		{Name: "Bubo bubo", MatchType: vlib.Exact, BestResult: r1,
+			Results: []*vlib.ResultData{r1, r2}},
+		{Name: "Bubo bubu", MatchType: vlib.Fuzzy, BestResult: r1},
+		{Name: "Bubo", MatchType: vlib.Exact, BestResult: r1,
+			Results: []*vlib.ResultData{r1, r3}},
+		{Name: "Aus bus", MatchType: vlib.NoMatch},
+		{Name: "Bubo bubox", Error: "timeout"},
+	}
+	res := reviews(names)
+	require.Len(t, res, 3)
+	assert.Equal(t, "Bubo bubu", res[0].Name.Name)
+	assert.Equal(t, "Bubo", res[1].Name.Name)
+	assert.Equal(t, 1, res[1].Index)
+	require.Len(t, res[1].Candidates, 2)
+	assert.True(t, res[1].Candidates[0].Best)
+	assert.False(t, res[1].Candidates[1].Best)
+	assert.Equal(t, "12:c", res[1].Candidates[1].Value)
+	assert.Empty(t, res[2].Candidates)
+}
+
+func TestReviewExport(t *testing.T) {
+	names := []vlib.Name{
+		{Name: "Bubo bubu", MatchType: vlib.Fuzzy,
+			BestResult: &vlib.ResultData{DataSourceID: 1, RecordID: "a:b",
+				MatchType: vlib.Fuzzy, MatchedName: "Bubo bubo"}},
+		{Name: "NotAName", MatchType: vlib.NoMatch},
+		{Name: "Pica pica", MatchType: vlib.Exact,
+			BestResult: &vlib.ResultData{DataSourceID: 1, RecordID: "c",
+				MatchType: vlib.Exact, MatchedName: "Pica pica"}},
+	}
+	token, err := savedReviews.add(plainNames(names))
+	require.Nil(t, err)
+
+	form := func(export string) url.Values {
+		f := make(url.Values)
+		f.Set("token", token)
+		// names of the form are ignored, reviewed names are kept by the
+		// server.
+		f["name"] = []string{"Pica pica", "Bubo bubu"}
+		f.Set("choice_0", "1:a:b")
+		f.Set("choice_1", "reject")
+		f.Set("curator", "Jane")
+		f.Set("export", export)
+		return f
+	}
+	vfr := new(vtest.FakeVerifier)
+	export := func(f url.Values) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/review/export",
+			strings.NewReader(f.Encode()),
+		)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		gnv := gnverifier.New(config.New(), vfr)
+		return rec, reviewExport(gnv)(c)
+	}
+
+	rec, err := export(form("decisions"))
+	require.Nil(t, err)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition),
+		"decisions.csv")
+	rows := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Name,DataSourceId,RecordId,Curator,Date", rows[0])
+	assert.True(t, strings.HasPrefix(rows[1], "Bubo bubu,1,a:b,Jane,"))
+	assert.True(t, strings.HasPrefix(rows[2], "NotAName,,reject,Jane,"))
+
+	f := form("results")
+	f.Set("choice_0", "skip")
+	rec, err = export(f)
+	require.Nil(t, err)
+	rows = strings.Split(rec.Body.String(), "\n")
+	require.Len(t, rows, 4)
+	assert.True(t, strings.HasSuffix(rows[0], "Decision,Curator,DecisionDate"))
+	assert.True(t, strings.HasSuffix(rows[1], ",,,"))
+	assert.Contains(t, rows[2], "reject,Jane,")
+	assert.Equal(t, 0, vfr.VerifyCallCount())
+
+	f = form("results")
+	f.Set("token", "unknown")
+	_, err = export(f)
+	var he *echo.HTTPError
+	require.True(t, errors.As(err, &he))
+	assert.Equal(t, http.StatusGone, he.Code)
+
+	f = form("decisions")
+	f.Set("choice_0", "one:a")
+	_, err = export(f)
+	require.True(t, errors.As(err, &he))
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+}
+
+func TestReviewCache(t *testing.T) {
+	rc := newReviewCache(time.Hour, 2)
+	var tokens []string
+	for range 3 {
+		token, err := rc.add(nil)
+		require.Nil(t, err)
+		tokens = append(tokens, token)
+	}
+	_, ok := rc.get(tokens[0])
+	assert.False(t, ok)
+	_, ok = rc.get(tokens[2])
+	assert.True(t, ok)
+
+	rc = newReviewCache(0, 2)
+	token, err := rc.add(nil)
+	require.Nil(t, err)
+	_, ok = rc.get(token)
+	assert.False(t, ok)
+}
+
+func TestServeShutdown(t *testing.T) {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	s := &http.Server{Addr: "127.0.0.1:0"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, e, s, time.Second)
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	cfg := config.New(config.OptWebBasePath("/tools/gnverifier/"))
+	vfr := new(vtest.FakeVerifier)
+	gnv := gnverifier.New(cfg, vfr)
+	e, err := newEcho(gnv)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		path     string
+		code     int
+		contains string
+	}{
+		{"/tools/gnverifier/", http.StatusOK, `href="/tools/gnverifier/about"`},
+		{"/tools/gnverifier", http.StatusOK, `action='/tools/gnverifier/'`},
+		{"/tools/gnverifier/about", http.StatusOK, "/tools/gnverifier/static/styles/screen.css"},
+		{"/tools/gnverifier/review", http.StatusOK, `action='/tools/gnverifier/review'`},
+		{"/tools/gnverifier/static/js/home.js", http.StatusOK, "advanced_options"},
+		{"/about", http.StatusNotFound, ""},
+	}
+	for _, v := range tests {
+		req := httptest.NewRequest(http.MethodGet, v.path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, v.code, rec.Code, v.path)
+		assert.Contains(t, rec.Body.String(), v.contains, v.path)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	cfg := config.New(
+		config.OptWebCORSOrigins([]string{"https://example.org"}),
+	)
+	vfr := new(vtest.FakeVerifier)
+	vfr.NameStringReturns(vlib.NameStringOutput{}, nil)
+	gnv := gnverifier.New(cfg, vfr)
+	e, err := newEcho(gnv)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "/name_strings/widget/Bubo", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://example.org")
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://example.org",
+		rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	req = httptest.NewRequest(http.MethodGet, "/name_strings/widget/Bubo", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://other.org")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	req = httptest.NewRequest(http.MethodGet, "/static/js/widget.js", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "data-gnverifier-name")
+}
+
+func TestOpenAPI(t *testing.T) {
+	cfg := config.New(config.OptWebBasePath("/gnv"))
+	vfr := new(vtest.FakeVerifier)
+	vfr.DataSourcesReturns([]vlib.DataSource{{ID: 1, Title: "Catalogue of Life"}}, nil)
+	gnv := gnverifier.New(cfg, vfr)
+	e, err := newEcho(gnv)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/gnv/api/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var spec struct {
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Enum []string `json:"enum"`
+				} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &spec)
+	assert.Nil(t, err)
+	assert.Equal(t, "/gnv", spec.Servers[0].URL)
+	assert.Contains(t, spec.Paths, "/data_sources/{id}")
+	assert.Contains(t, spec.Paths, "/name_strings/{id}")
+	assert.Contains(t, spec.Paths["/"], "get")
+	assert.Contains(t, spec.Paths["/"], "post")
+	assert.NotContains(t, spec.Paths, "/about")
+	assert.Contains(t, spec.Components.Schemas, "DataSource")
+	assert.Contains(t, spec.Components.Schemas, "ResultData")
+	enum := spec.Components.Schemas["ResultData"].Properties["matchType"].Enum
+	assert.Contains(t, enum, "Exact")
+	assert.Contains(t, enum, "Fuzzy")
+	assert.NotContains(t, enum, "N/A")
+
+	req = httptest.NewRequest(http.MethodGet, "/gnv/api/explorer", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/gnv/api/openapi.json")
+
+	req = httptest.NewRequest(http.MethodGet, "/gnv/data_sources?format=json", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"title":"Catalogue of Life"`)
+}
+
+func TestTLSConfig(t *testing.T) {
+	res, err := tlsConfig(config.New())
+	assert.Nil(t, err)
+	assert.Nil(t, res)
+
+	_, err = tlsConfig(config.New(config.OptWebTLSCert("cert.pem")))
+	assert.NotNil(t, err)
+
+	_, err = tlsConfig(config.New(
+		config.OptWebTLSCert("nofile.pem"),
+		config.OptWebTLSKey("nofile.key"),
+	))
+	assert.NotNil(t, err)
+
+	res, err = tlsConfig(config.New(
+		config.OptWebTLSSelfSigned(true),
+		config.OptWebAddress("192.168.1.10"),
+	))
+	assert.Nil(t, err)
+	assert.Len(t, res.Certificates, 1)
+	cert, err := x509.ParseCertificate(res.Certificates[0].Certificate[0])
+	assert.Nil(t, err)
+	assert.Contains(t, cert.DNSNames, "localhost")
+	assert.Nil(t, cert.VerifyHostname("192.168.1.10"))
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	keys := `
+- key: secret1
+  client: botany
+  namesPerDay: 4
+- key: secret2
+  client: zoology
+`
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(keys), 0644))
+
+	tests := []struct {
+		msg      string
+		publicUI bool
+		path     string
+		key      string
+		code     int
+	}{
+		{"no key", false, "/about", "", http.StatusUnauthorized},
+		{"bad key", false, "/about", "wrong", http.StatusUnauthorized},
+		{"good key", false, "/about", "secret1", http.StatusOK},
+		{"query key", false, "/about?api_key=secret1", "", http.StatusUnauthorized},
+		{"static", false, "/static/js/home.js", "", http.StatusOK},
+		{"public ui", true, "/about", "", http.StatusOK},
+		{"public ui api", true, "/?names=Bubo+bubo&format=json", "", http.StatusUnauthorized},
+		{"api", true, "/?names=Bubo+bubo&format=json", "secret2", http.StatusOK},
+		{"too large", true, "/?names=A+b%0AC+d%0AE+f%0AG+h%0AI+j&format=json",
+			"secret1", http.StatusRequestEntityTooLarge},
+	}
+
+	for _, v := range tests {
+		cfg := config.New(
+			config.OptWebAPIKeysFile(path),
+			config.OptWebPublicUI(v.publicUI),
+		)
+		vfr := new(vtest.FakeVerifier)
+		vfr.VerifyReturns(verifications(t), nil)
+		gnv := gnverifier.New(cfg, vfr)
+		e, err := newEcho(gnv)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, v.path, nil)
+		if v.key != "" {
+			req.Header.Set("X-API-Key", v.key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, v.code, rec.Code, v.msg)
+		if v.code == http.StatusTooManyRequests {
+			assert.NotEmpty(t, rec.Header().Get("Retry-After"), v.msg)
+		}
+	}
+}
+
+func TestAPIKeyQuotas(t *testing.T) {
+	keys := `
+- key: secret1
+  client: botany
+  namesPerDay: 4
+- public: true
+  namesPerDay: 3
+`
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	require.Nil(t, os.WriteFile(path, []byte(keys), 0644))
+	cfg := config.New(
+		config.OptWebAPIKeysFile(path),
+		config.OptWebPublicUI(true),
+		config.OptNamesNumThreshold(3),
+	)
+	vfr := new(vtest.FakeVerifier)
+	vfr.VerifyReturns(verifications(t), nil)
+	e, err := newEcho(gnverifier.New(cfg, vfr))
+	require.Nil(t, err)
+
+	send := func(method, path, key string) *httptest.ResponseRecorder {
+		var req *http.Request
+		if method == http.MethodPost {
+			f := make(url.Values)
+			f.Set("names", "A b\nC d")
+			f.Set("format", "json")
+			req = httptest.NewRequest(method, path, strings.NewReader(f.Encode()))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		} else {
+			req = httptest.NewRequest(method, path, nil)
+		}
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// a redirected POST is charged only once, on GET.
+	assert.Equal(t, http.StatusFound, send(http.MethodPost, "/", "secret1").Code)
+	two := "/?names=A+b%0AC+d&format=json"
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, two, "secret1").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, two, "secret1").Code)
+	rec := send(http.MethodGet, two, "secret1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// HTML verification without a key uses the public quota.
+	html := "/?names=A+b%0AC+d"
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, html, "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodGet, html, "").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/about", "").Code)
+	four := "/?names=A+b%0AC+d%0AE+f%0AG+h"
+	assert.Equal(t, http.StatusRequestEntityTooLarge,
+		send(http.MethodGet, four, "").Code)
+
+	_, err = newKeyStore([]apiKey{{Key: "a", Public: true}})
+	assert.NotNil(t, err)
+}
+
+func TestKeyStoreAllow(t *testing.T) {
+	ks, err := newKeyStore([]apiKey{
+		{Key: "k", Client: "test", NamesPerDay: 10, RequestsPerMinute: 2},
+	})
+	assert.Nil(t, err)
+	now := time.Date(2025, 1, 1, 23, 59, 0, 0, time.UTC)
+	ks.now = func() time.Time { return now }
+	cl, ok := ks.find("k")
+	assert.True(t, ok)
+
+	wait, _ := ks.allow(cl, 4)
+	assert.Zero(t, wait)
+	wait, _ = ks.allow(cl, 4)
+	assert.Zero(t, wait)
+
+	// burst of 2 requests is used up
+	wait, reason := ks.allow(cl, 1)
+	assert.Equal(t, 30*time.Second, wait)
+	assert.Equal(t, "rate limit is exceeded", reason)
+
+	now = now.Add(30 * time.Second)
+	wait, reason = ks.allow(cl, 4)
+	assert.Equal(t, 30*time.Second, wait)
+	assert.Equal(t, "daily names quota is exceeded", reason)
+
+	// quota resets on the next UTC day
+	now = now.Add(30 * time.Second)
+	wait, _ = ks.allow(cl, 4)
+	assert.Zero(t, wait)
+	assert.Equal(t, 4, cl.used())
+
+	_, err = newKeyStore([]apiKey{{Key: "a"}, {Key: "a"}})
+	assert.NotNil(t, err)
+}
+
 func verifications(t *testing.T) vlib.Output {
 	c := cassette.New("dss")
 	data, err := os.ReadFile("../verifrest/fixtures/names.yaml")
@@ -144,3 +779,21 @@ func verifications(t *testing.T) vlib.Output {
 	assert.Nil(t, err)
 	return res
 }
+
+func TestKeyStoreFind(t *testing.T) {
+	ks, err := newKeyStore([]apiKey{
+		{Key: "secret1", Client: "botany"},
+		{Key: "secret2", Client: "zoology"},
+		{Public: true},
+	})
+	assert.Nil(t, err)
+
+	cl, ok := ks.find("secret2")
+	assert.True(t, ok)
+	assert.Equal(t, "zoology", cl.Client)
+
+	for _, v := range []string{"", "secret", "secret12", "wrong"} {
+		_, ok = ks.find(v)
+		assert.False(t, ok, v)
+	}
+}
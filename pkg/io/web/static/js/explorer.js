@@ -0,0 +1,140 @@
+// API explorer builds a form for every operation of the OpenAPI
+// specification and shows responses of the requests sent from these forms.
+$(document).ready(function(){
+  var $root = $("#explorer");
+  var specURL = $root.data("spec");
+
+  $.getJSON(specURL)
+    .done(function(spec) {
+      $root.empty();
+      var base = spec.servers[0].url.replace(/\/$/, "");
+      Object.keys(spec.paths).sort().forEach(function(path) {
+        var item = spec.paths[path];
+        Object.keys(item).forEach(function(method) {
+          $root.append(operation(base, path, method, item[method]));
+        });
+      });
+    })
+    .fail(function(xhr) {
+      $root.text("Cannot load specification: " + xhr.status);
+    });
+
+  function parameters(op) {
+    var res = (op.parameters || []).slice();
+    var body = op.requestBody &&
+      op.requestBody.content["application/x-www-form-urlencoded"];
+    if (body) {
+      Object.keys(body.schema.properties).forEach(function(name) {
+        res.push({
+          name: name,
+          in: "form",
+          required: (body.schema.required || []).indexOf(name) >= 0,
+          schema: body.schema.properties[name]
+        });
+      });
+    }
+    return res;
+  }
+
+  function field(p) {
+    var schema = p.schema.items || p.schema;
+    if (schema.enum) {
+      var $sel = $("<select>").attr("name", p.name);
+      $sel.append($("<option>").val("").text(""));
+      schema.enum.forEach(function(v) {
+        $sel.append($("<option>").val(v).text(v));
+      });
+      return $sel;
+    }
+    if (p.name === "names") {
+      return $("<textarea>").attr({ name: p.name, rows: 4, cols: 50 });
+    }
+    return $("<input>").attr({ name: p.name, type: "text" });
+  }
+
+  function operation(base, path, method, op) {
+    var $op = $("<div>").addClass("operation");
+    $op.append($("<h3>").text(method.toUpperCase() + " " + path));
+    $op.append($("<p>").text(op.summary));
+    if (op.description) {
+      $op.append($("<p>").text(op.description));
+    }
+
+    var params = parameters(op);
+    var $form = $("<form>");
+    var $table = $("<table>");
+    params.forEach(function(p) {
+      var label = p.name + (p.required ? " *" : "");
+      if (p.schema.type === "array") {
+        label += " (comma-separated)";
+      }
+      $table.append(
+        $("<tr>")
+          .append($("<td>").text(label))
+          .append($("<td>").append(field(p)))
+          .append($("<td>").text(p.description || ""))
+      );
+    });
+    $form.append($table);
+    $form.append($("<input>").attr({ type: "submit", value: "Send" }));
+    var $out = $("<pre>").addClass("code").hide();
+    $op.append($form).append($out);
+
+    $form.on("submit", function(e) {
+      e.preventDefault();
+      send(base, path, method, params, $form, $out);
+    });
+    return $op;
+  }
+
+  function send(base, path, method, params, $form, $out) {
+    var query = [];
+    var form = [];
+    params.forEach(function(p) {
+      var val = $.trim($form.find("[name='" + p.name + "']").val());
+      if (val === "") {
+        return;
+      }
+      if (p.in === "path") {
+        path = path.replace("{" + p.name + "}", encodeURIComponent(val));
+        return;
+      }
+      var vals = p.schema.type === "array" ? val.split(",") : [val];
+      vals.forEach(function(v) {
+        var pair = encodeURIComponent(p.name) + "=" +
+          encodeURIComponent($.trim(v));
+        (p.in === "form" ? form : query).push(pair);
+      });
+    });
+
+    var url = base + path;
+    if (query.length > 0) {
+      url += "?" + query.join("&");
+    }
+    var opts = { method: method.toUpperCase() };
+    if (method === "post") {
+      opts.headers = { "Content-Type": "application/x-www-form-urlencoded" };
+      opts.body = form.join("&");
+    }
+
+    $out.show().text("Sending " + opts.method + " " + url + " ...");
+    fetch(url, opts)
+      .then(function(resp) {
+        return resp.text().then(function(text) {
+          var head = resp.status + " " + resp.statusText + "\n" + resp.url;
+          $out.text(head + "\n\n" + pretty(text));
+        });
+      })
+      .catch(function(err) {
+        $out.text("Request failed: " + err.message);
+      });
+  }
+
+  function pretty(text) {
+    try {
+      return JSON.stringify(JSON.parse(text), null, 2);
+    } catch (e) {
+      return text;
+    }
+  }
+});
@@ -3,4 +3,22 @@ $(document).ready(function(){
     e.preventDefault();
     $("#advanced_selections").toggle();
   });
+
+  // a preset fills the form with settings of a profile.
+  $("#preset").on("change", function() {
+    var opt = $(this).find(":selected");
+    if (opt.val() === "") {
+      return;
+    }
+    var ds = String(opt.data("ds")).split(",");
+    $("input[name='ds']").each(function() {
+      $(this).prop("checked", ds.indexOf($(this).val()) >= 0);
+    });
+    $("#vernaculars").val(opt.data("vernaculars"));
+    ["all_matches", "species_group", "fuzzy_relaxed", "fuzzy_uninomial"]
+      .forEach(function(id) {
+        $("#" + id).prop("checked", opt.data(id) === true);
+      });
+    $("#advanced_selections").show();
+  });
 });
@@ -0,0 +1,101 @@
+// GNverifier widget loader.
+//
+// Add elements with data-gnverifier-name attribute to a page and load this
+// script from a GNverifier server:
+//
+//   <div data-gnverifier-name="Bubo bubo" data-gnverifier-sources="1,11"></div>
+//   <script src="https://example.org/static/js/widget.js"></script>
+//
+// The script fetches verification results for every such element from
+// the server it was loaded from and renders them inside the element. The
+// server has to allow the page's origin via CORS settings.
+(function () {
+  "use strict";
+
+  var script = document.currentScript;
+  if (!script) {
+    return;
+  }
+  var base = script.src.replace(/\/static\/js\/widget\.js(\?.*)?$/, "");
+
+  function absolutize(doc) {
+    var attrs = [["a", "href"], ["img", "src"], ["link", "href"]];
+    attrs.forEach(function (pair) {
+      doc.querySelectorAll(pair[0] + "[" + pair[1] + "]").forEach(function (el) {
+        var val = el.getAttribute(pair[1]);
+        el.setAttribute(pair[1], new URL(val, base + "/").href);
+      });
+    });
+    doc.querySelectorAll("a[href]").forEach(function (el) {
+      el.setAttribute("target", "_blank");
+      el.setAttribute("rel", "noopener");
+    });
+  }
+
+  function render(el, html) {
+    var doc = new DOMParser().parseFromString(html, "text/html");
+    absolutize(doc);
+    var root = el.shadowRoot || el.attachShadow({ mode: "open" });
+    root.innerHTML = "";
+    doc.querySelectorAll("link[rel=stylesheet]").forEach(function (link) {
+      root.appendChild(link.cloneNode());
+    });
+    var body = document.createElement("div");
+    body.innerHTML = doc.body.innerHTML;
+    root.appendChild(body);
+  }
+
+  function load(el) {
+    var name = el.getAttribute("data-gnverifier-name");
+    if (!name) {
+      return;
+    }
+    var url = base + "/name_strings/widget/" + encodeURIComponent(name);
+    var params = [];
+    var sources = el.getAttribute("data-gnverifier-sources");
+    if (sources) {
+      params.push("data_sources=" + encodeURIComponent(sources));
+    }
+    if (el.getAttribute("data-gnverifier-all-matches") === "true") {
+      params.push("all_matches=true");
+    }
+    if (params.length > 0) {
+      url += "?" + params.join("&");
+    }
+
+    var headers = {};
+    var key = script.getAttribute("data-gnverifier-key");
+    if (key) {
+      headers["X-API-Key"] = key;
+    }
+
+    fetch(url, { headers: headers })
+      .then(function (resp) {
+        if (!resp.ok) {
+          throw new Error("GNverifier responded with " + resp.status);
+        }
+        return resp.text();
+      })
+      .then(function (html) {
+        render(el, html);
+      })
+      .catch(function (err) {
+        el.textContent = name;
+        if (window.console) {
+          console.warn("GNverifier widget:", err.message);
+        }
+      });
+  }
+
+  function loadAll() {
+    document.querySelectorAll("[data-gnverifier-name]").forEach(load);
+  }
+
+  window.gnverifierWidget = { load: load, loadAll: loadAll };
+
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", loadAll);
+  } else {
+    loadAll();
+  }
+})();
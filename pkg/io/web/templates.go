@@ -6,6 +6,7 @@ import (
 	"html/template"
 	"io"
 	"path"
+	"strconv"
 	"strings"
 
 	vlib "github.com/gnames/gnlib/ent/verifier"
@@ -30,15 +31,17 @@ func (t *echoTempl) Render(
 	return t.templates.ExecuteTemplate(w, name, data)
 }
 
-func NewTemplate() (*echoTempl, error) {
-	t, err := parseFiles()
+// NewTemplate parses embedded HTML templates. All links generated by
+// templates start with the given basePath.
+func NewTemplate(basePath string) (*echoTempl, error) {
+	t, err := parseFiles(basePath)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse templates: %w", err)
 	}
 	return &echoTempl{t}, nil
 }
 
-func parseFiles() (*template.Template, error) {
+func parseFiles(basePath string) (*template.Template, error) {
 	var err error
 	var t *template.Template
 
@@ -65,7 +68,7 @@ func parseFiles() (*template.Template, error) {
 		} else {
 			tmpl = t.New(name)
 		}
-		addFuncs(tmpl)
+		addFuncs(tmpl, basePath)
 		_, err = tmpl.ParseFS(tmpls, filename)
 		if err != nil {
 			return nil, err
@@ -74,11 +77,27 @@ func parseFiles() (*template.Template, error) {
 	return t, nil
 }
 
-func addFuncs(tmpl *template.Template) {
+func addFuncs(tmpl *template.Template, basePath string) {
 	tmpl.Funcs(template.FuncMap{
+		"basePath": func() string {
+			return basePath
+		},
 		"isEven": func(i int) bool {
 			return i%2 == 0
 		},
+		"join": func(v any) string {
+			switch x := v.(type) {
+			case []int:
+				ss := make([]string, len(x))
+				for i := range x {
+					ss[i] = strconv.Itoa(x[i])
+				}
+				return strings.Join(ss, ",")
+			case []string:
+				return strings.Join(x, ",")
+			}
+			return ""
+		},
 		"classification": func(pathStr, rankStr string) string {
 			if pathStr == "" {
 				return ""
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/gnames/gnverifier/pkg/config"
+)
+
+// tlsConfig creates TLS configuration for the web server. It returns nil
+// if the server should use plain HTTP.
+func tlsConfig(cfg config.Config) (*tls.Config, error) {
+	var cert tls.Certificate
+	var err error
+
+	switch {
+	case cfg.WebTLSCert != "" && cfg.WebTLSKey != "":
+		cert, err = tls.LoadX509KeyPair(cfg.WebTLSCert, cfg.WebTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load TLS certificate: %w", err)
+		}
+	case cfg.WebTLSCert != "" || cfg.WebTLSKey != "":
+		return nil, errors.New("TLS needs both certificate and key files")
+	case cfg.WebTLSSelfSigned:
+		cert, err = selfSignedCert(cfg.WebAddress)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create self-signed certificate: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	res := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	return res, nil
+}
+
+// selfSignedCert generates an in-memory certificate valid for localhost
+// and the given host. Browsers will warn about such certificate, so it
+// is only suitable for development.
+func selfSignedCert(host string) (tls.Certificate, error) {
+	var res tls.Certificate
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return res, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return res, err
+	}
+
+	now := time.Now()
+	tmpl := x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"GNverifier development"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if host != "" && host != "localhost" {
+		if ip := net.ParseIP(host); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		return res, err
+	}
+
+	res = tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+	return res, nil
+}